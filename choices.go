@@ -0,0 +1,210 @@
+package gomarsys
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// multiChoiceSeparator separates choice ids of multi-choice field values.
+const multiChoiceSeparator = ","
+
+// ChoiceTranslator converts values of single-choice and multi-choice fields
+// between choice ids and human readable labels. Choices are fetched once per
+// field and cached until Reset is called.
+type ChoiceTranslator struct {
+	registry *FieldRegistry
+
+	mu      sync.RWMutex
+	choices map[int][]FieldChoice
+}
+
+func NewChoiceTranslator(registry *FieldRegistry) *ChoiceTranslator {
+	return &ChoiceTranslator{
+		registry: registry,
+		choices:  make(map[int][]FieldChoice),
+	}
+}
+
+// Reset drops cached choices.
+func (t *ChoiceTranslator) Reset() {
+	t.mu.Lock()
+	t.choices = make(map[int][]FieldChoice)
+	t.mu.Unlock()
+}
+
+// IsChoiceField reports whether the field is a single-choice or multi-choice
+// field.
+func (t *ChoiceTranslator) IsChoiceField(ctx context.Context, fieldID int) (bool, error) {
+	field, err := t.registry.Field(ctx, fieldID)
+	if err != nil {
+		return false, err
+	}
+
+	return isChoiceType(field.ApplicationType), nil
+}
+
+// Labels translates a field value holding one or more choice ids to labels.
+func (t *ChoiceTranslator) Labels(ctx context.Context, fieldID int, value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	choices, err := t.fieldChoices(ctx, fieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := strings.Split(value, multiChoiceSeparator)
+	labels := make([]string, len(ids))
+
+	for i, id := range ids {
+		id = strings.TrimSpace(id)
+		found := false
+
+		for _, choice := range choices {
+			if choice.ID == id {
+				labels[i] = choice.Choice
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("unknown choice id '%s' of field %d", id, fieldID)
+		}
+	}
+
+	return labels, nil
+}
+
+// Value translates labels to a field value of choice ids. Labels are matched
+// case-insensitively.
+func (t *ChoiceTranslator) Value(ctx context.Context, fieldID int, labels []string) (string, error) {
+	if len(labels) == 0 {
+		return "", nil
+	}
+
+	choices, err := t.fieldChoices(ctx, fieldID)
+	if err != nil {
+		return "", err
+	}
+
+	ids := make([]string, len(labels))
+
+	for i, label := range labels {
+		found := false
+
+		for _, choice := range choices {
+			if strings.EqualFold(choice.Choice, label) {
+				ids[i] = choice.ID
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return "", fmt.Errorf("unknown choice '%s' of field %d", label, fieldID)
+		}
+	}
+
+	return strings.Join(ids, multiChoiceSeparator), nil
+}
+
+// DecodeUser returns the contact data with choice fields translated to
+// labels: a string for single-choice and a []string for multi-choice fields.
+// Other fields are returned as is.
+func (t *ChoiceTranslator) DecodeUser(ctx context.Context, user *User) (map[int]interface{}, error) {
+	values := make(map[int]interface{}, len(user.Data))
+
+	for id, value := range user.Data {
+		decoded, err := t.decodeValue(ctx, id, value)
+		if err != nil {
+			return nil, err
+		}
+		values[id] = decoded
+	}
+
+	return values, nil
+}
+
+// EncodeUser is the reverse of DecodeUser: it accepts strings or []string
+// labels for choice fields and strings for other fields.
+func (t *ChoiceTranslator) EncodeUser(ctx context.Context, values map[int]interface{}) (*User, error) {
+	user := &User{Data: make(map[int]string, len(values))}
+
+	for id, value := range values {
+		var labels []string
+
+		switch v := value.(type) {
+		case string:
+			choice, err := t.IsChoiceField(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if !choice {
+				user.Data[id] = v
+				continue
+			}
+			labels = []string{v}
+		case []string:
+			labels = v
+		default:
+			return nil, fmt.Errorf("unsupported value type %T of field %d", value, id)
+		}
+
+		encoded, err := t.Value(ctx, id, labels)
+		if err != nil {
+			return nil, err
+		}
+		user.Data[id] = encoded
+	}
+
+	return user, nil
+}
+
+func (t *ChoiceTranslator) decodeValue(ctx context.Context, fieldID int, value string) (interface{}, error) {
+	field, err := t.registry.Field(ctx, fieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch field.ApplicationType {
+	case FieldTypeSingleChoice:
+		labels, err := t.Labels(ctx, fieldID, value)
+		if err != nil || len(labels) == 0 {
+			return "", err
+		}
+		return labels[0], nil
+	case FieldTypeMultiChoice:
+		return t.Labels(ctx, fieldID, value)
+	}
+
+	return value, nil
+}
+
+func (t *ChoiceTranslator) fieldChoices(ctx context.Context, fieldID int) ([]FieldChoice, error) {
+	t.mu.RLock()
+	choices, ok := t.choices[fieldID]
+	t.mu.RUnlock()
+
+	if ok {
+		return choices, nil
+	}
+
+	choices, err := t.registry.fields.ChoicesContext(ctx, fieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	t.choices[fieldID] = choices
+	t.mu.Unlock()
+
+	return choices, nil
+}
+
+func isChoiceType(applicationType string) bool {
+	return applicationType == FieldTypeSingleChoice || applicationType == FieldTypeMultiChoice
+}
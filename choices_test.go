@@ -0,0 +1,104 @@
+package gomarsys
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestChoicesClient() *ClientMock {
+	client := newTestFieldsClient()
+	client.On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/field/5/choice"
+	})).Return([]byte(`{"replyCode":0,"replyText":"OK","data":[{"id":"1","choice":"Male","bit_position":1},{"id":"2","choice":"Female","bit_position":2}]}`), nil)
+	client.On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/field/1235/choice"
+	})).Return([]byte(`{"replyCode":0,"replyText":"OK","data":[{"id":"1","choice":"Acme","bit_position":1},{"id":"2","choice":"Globex","bit_position":2},{"id":"3","choice":"Initech","bit_position":3}]}`), nil)
+
+	return client
+}
+
+func TestChoiceTranslator_Labels(t *testing.T) {
+	client := newTestChoicesClient()
+	translator := NewChoiceTranslator(NewFieldRegistry(NewFields(client)))
+
+	ctx := context.Background()
+
+	labels, err := translator.Labels(ctx, 1235, "1,3")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Acme", "Initech"}, labels)
+
+	value, err := translator.Value(ctx, 1235, []string{"globex", "Acme"})
+	require.NoError(t, err)
+	assert.Equal(t, "2,1", value)
+
+	_, err = translator.Value(ctx, Gender, []string{"unknown"})
+	require.Error(t, err)
+
+	client.AssertNumberOfCalls(t, "Send", 2)
+}
+
+func TestChoiceTranslator_DecodeEncodeUser(t *testing.T) {
+	client := newTestChoicesClient()
+	translator := NewChoiceTranslator(NewFieldRegistry(NewFields(client)))
+
+	ctx := context.Background()
+
+	values, err := translator.DecodeUser(ctx, &User{Data: map[int]string{
+		EMail:  "test@test.ru",
+		Gender: "2",
+		1235:   "2,3",
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]interface{}{
+		EMail:  "test@test.ru",
+		Gender: "Female",
+		1235:   []string{"Globex", "Initech"},
+	}, values)
+
+	user, err := translator.EncodeUser(ctx, values)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]string{
+		EMail:  "test@test.ru",
+		Gender: "2",
+		1235:   "2,3",
+	}, user.Data)
+}
+
+func TestUsers_UpdateUserFromLabels(t *testing.T) {
+	client := newTestChoicesClient()
+	client.On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/contact"
+	})).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		var v struct {
+			Contacts []map[string]string `json:"contacts"`
+		}
+		require.NoError(t, json.Unmarshal(req.Body, &v))
+		assert.Equal(t, map[string]string{
+			"3":    "test@test.ru",
+			"5":    "1",
+			"1235": "3,1",
+		}, v.Contacts[0])
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"ids":[111111]}}`), nil)
+
+	translator := NewChoiceTranslator(NewFieldRegistry(NewFields(client)))
+	user := NewUsers(client, WithChoiceTranslator(translator))
+
+	id, err := user.UpdateUserFrom(struct {
+		Email  string   `emarsys:"email"`
+		Gender string   `emarsys:"gender,label"`
+		Brands []string `emarsys:"favourite_brands,label"`
+	}{
+		Email:  "test@test.ru",
+		Gender: "Male",
+		Brands: []string{"Initech", "Acme"},
+	}, EMail)
+	require.NoError(t, err)
+	assert.Equal(t, "111111", id)
+}
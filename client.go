@@ -2,9 +2,11 @@ package gomarsys
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha1"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -44,8 +46,10 @@ type ClientError struct {
 }
 
 type options struct {
-	client *http.Client
-	host   string
+	client      *http.Client
+	host        string
+	retryPolicy *RetryPolicy
+	rateLimiter *rateLimiter
 }
 
 func WithCustomHost(host string) ClientOptions {
@@ -60,29 +64,67 @@ func WithClientTimeout(t time.Duration) ClientOptions {
 	}
 }
 
+func (m RequestMethod) String() string {
+	switch m {
+	case requestPost:
+		return http.MethodPost
+	case requestGet:
+		return http.MethodGet
+	case requestPut:
+		return http.MethodPut
+	}
+
+	return ""
+}
+
 func (clientError *ClientError) Error() string {
 	return clientError.err.Error()
 }
 
+func (clientError *ClientError) Unwrap() error {
+	return clientError.err
+}
+
 func newClientError(err error) error {
 	return &ClientError{err}
 }
 
 type Client struct {
-	auth   auth
-	client *http.Client
-	host   string
+	auth        auth
+	client      *http.Client
+	host        string
+	retryPolicy *RetryPolicy
+	rateLimiter *rateLimiter
 }
 
 type Request struct {
 	Path   string
 	Method RequestMethod
 	Body   []byte
+	Header http.Header
+}
+
+// ResponseBody is the stream returned by Client.SendIO. It keeps the status
+// code and headers of the response, e.g. to inspect Content-Range.
+type ResponseBody struct {
+	io.ReadCloser
+	StatusCode int
+	Header     http.Header
+}
+
+func newResponseBody(resp *http.Response) *ResponseBody {
+	return &ResponseBody{
+		ReadCloser: resp.Body,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+	}
 }
 
 type ClientInterface interface {
 	Send(r *Request) ([]byte, error)
 	SendIO(r *Request) (io.ReadCloser, error)
+	SendContext(ctx context.Context, r *Request) ([]byte, error)
+	SendIOContext(ctx context.Context, r *Request) (io.ReadCloser, error)
 }
 
 func NewClient(user string, secret string, clientOptions ...ClientOptions) ClientInterface {
@@ -100,22 +142,20 @@ func NewClient(user string, secret string, clientOptions ...ClientOptions) Clien
 			User:   user,
 			Secret: secret,
 		},
-		client: defaultOptions.client,
-		host:   defaultOptions.host,
+		client:      defaultOptions.client,
+		host:        defaultOptions.host,
+		retryPolicy: defaultOptions.retryPolicy,
+		rateLimiter: defaultOptions.rateLimiter,
 	}
 }
 
 func (c *Client) SendIO(r *Request) (io.ReadCloser, error) {
-	method := ""
+	return c.SendIOContext(context.Background(), r)
+}
 
-	switch r.Method {
-	case requestPost:
-		method = "POST"
-	case requestGet:
-		method = "GET"
-	case requestPut:
-		method = "PUT"
-	default:
+func (c *Client) SendIOContext(ctx context.Context, r *Request) (io.ReadCloser, error) {
+	method := r.Method.String()
+	if method == "" {
 		return nil, fmt.Errorf("unknown method: %d", r.Method)
 	}
 
@@ -126,11 +166,37 @@ func (c *Client) SendIO(r *Request) (io.ReadCloser, error) {
 
 	serverUrl.Path += "/"
 
-	req, err := http.NewRequest(method, serverUrl.String(), bytes.NewBuffer(r.Body))
+	if c.retryPolicy != nil {
+		return c.doWithRetries(ctx, serverUrl.String(), r)
+	}
+
+	resp, err := c.do(ctx, serverUrl.String(), r)
+	if err != nil {
+		return nil, err
+	}
+
+	return newResponseBody(resp), nil
+}
+
+// do performs a single request. On a status other than 200 or 206 the response is returned
+// alongside an *APIError with its body already consumed and closed, so callers
+// can inspect the status code and headers.
+func (c *Client) do(ctx context.Context, serverUrl string, r *Request) (*http.Response, error) {
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.wait(ctx); err != nil {
+			return nil, newClientError(err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.Method.String(), serverUrl, bytes.NewBuffer(r.Body))
 	if err != nil {
 		return nil, err
 	}
 
+	for key, values := range r.Header {
+		req.Header[key] = values
+	}
+
 	req.Header.Set("X-WSSE", c.getWSSEHeader())
 	req.Header.Set("Content-Type", "application/json")
 
@@ -139,15 +205,30 @@ func (c *Client) SendIO(r *Request) (io.ReadCloser, error) {
 		return nil, newClientError(err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error response, code: %d", resp.StatusCode)
+	if c.rateLimiter != nil {
+		c.rateLimiter.update(resp)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		defer func() { _ = resp.Body.Close() }()
+
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+		apiError := newAPIError(resp.StatusCode, body, r)
+		apiError.Header = resp.Header
+
+		return resp, apiError
 	}
 
-	return resp.Body, nil
+	return resp, nil
 }
 
 func (c *Client) Send(r *Request) ([]byte, error) {
-	stream, err := c.SendIO(r)
+	return c.SendContext(context.Background(), r)
+}
+
+func (c *Client) SendContext(ctx context.Context, r *Request) ([]byte, error) {
+	stream, err := c.SendIOContext(ctx, r)
 	if err != nil {
 		return nil, err
 	}
@@ -157,6 +238,21 @@ func (c *Client) Send(r *Request) ([]byte, error) {
 	return ioutil.ReadAll(stream)
 }
 
+// sendJSON sends r and decodes the response into v. A non-zero replyCode is
+// returned as *APIError.
+func sendJSON(ctx context.Context, client ClientInterface, r *Request, v interface{}) error {
+	response, err := client.SendContext(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	if apiError := checkReplyCode(response, r); apiError != nil {
+		return apiError
+	}
+
+	return json.Unmarshal(response, v)
+}
+
 func (c *Client) getWSSEHeader() string {
 	b := make([]byte, maxLengthWSSE)
 	for i := range b {
@@ -1,6 +1,7 @@
 package gomarsys
 
 import (
+	"context"
 	"io"
 
 	"github.com/stretchr/testify/mock"
@@ -23,3 +24,11 @@ func (c *ClientMock) Send(r *Request) ([]byte, error) {
 	args := c.Called(r)
 	return args.Get(0).([]byte), args.Error(1)
 }
+
+func (c *ClientMock) SendContext(_ context.Context, r *Request) ([]byte, error) {
+	return c.Send(r)
+}
+
+func (c *ClientMock) SendIOContext(_ context.Context, r *Request) (io.ReadCloser, error) {
+	return c.SendIO(r)
+}
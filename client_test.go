@@ -1,9 +1,12 @@
 package gomarsys
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -29,3 +32,51 @@ func TestClient_Send(t *testing.T) {
 	_, err := client.Send(r)
 	require.NoError(t, err)
 }
+
+func TestClient_SendContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		<-req.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient("test", "test", WithCustomHost(server.URL+"/"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	r := &Request{
+		Path:   "/some/test/path",
+		Method: requestGet,
+	}
+
+	_, err := client.SendContext(ctx, r)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestClient_SendAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusBadRequest)
+		_, _ = rw.Write([]byte(`{"replyCode":2008,"replyText":"No contact found with the external id: 3","data":""}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test", "test", WithCustomHost(server.URL+"/"))
+
+	r := &Request{
+		Path:   "/v2/contact/getdata",
+		Method: requestPost,
+	}
+
+	_, err := client.Send(r)
+	require.Error(t, err)
+
+	var apiError *APIError
+	require.True(t, errors.As(err, &apiError))
+	assert.Equal(t, http.StatusBadRequest, apiError.StatusCode)
+	assert.Equal(t, 2008, apiError.ReplyCode)
+	assert.Equal(t, "No contact found with the external id: 3", apiError.ReplyText)
+	assert.Equal(t, "/v2/contact/getdata", apiError.Path)
+	assert.Equal(t, http.MethodPost, apiError.Method)
+	assert.Contains(t, string(apiError.Body), `"replyCode":2008`)
+}
@@ -0,0 +1,359 @@
+package gomarsys
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+const (
+	defaultContactListPageSize = 10000
+
+	contactListSkipToken = "$skiptoken"
+	contactListTop       = "$top"
+)
+
+type ContactLists struct {
+	client ClientInterface
+}
+
+type ContactList struct {
+	ID      int
+	Name    string
+	Created string
+	Type    int
+}
+
+// ContactListResult is the outcome of adding contacts to or removing them
+// from a list. Errors holds the per-contact errors by key value.
+type ContactListResult struct {
+	Count  int
+	Errors map[string]error
+}
+
+func NewContactLists(client ClientInterface) *ContactLists {
+	return &ContactLists{
+		client: client,
+	}
+}
+
+func (c *ContactLists) List() ([]ContactList, error) {
+	return c.ListContext(context.Background())
+}
+
+func (c *ContactLists) ListContext(ctx context.Context) ([]ContactList, error) {
+	r := &Request{
+		Path:   "/v2/contactlist",
+		Method: requestGet,
+	}
+
+	var res struct {
+		ReplyCode int    `json:"replyCode"`
+		ReplyText string `json:"replyText"`
+		Data      []struct {
+			ID      flexibleID `json:"id"`
+			Name    string     `json:"name"`
+			Created string     `json:"created"`
+			Type    int        `json:"type"`
+		} `json:"data"`
+	}
+
+	if err := sendJSON(ctx, c.client, r, &res); err != nil {
+		return nil, err
+	}
+
+	lists := make([]ContactList, len(res.Data))
+	for i, item := range res.Data {
+		id, err := strconv.Atoi(string(item.ID))
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse contact list id '%s': %w", item.ID, err)
+		}
+
+		lists[i] = ContactList{
+			ID:      id,
+			Name:    item.Name,
+			Created: item.Created,
+			Type:    item.Type,
+		}
+	}
+
+	return lists, nil
+}
+
+func (c *ContactLists) Create(name, description string, keyID int, keyValues []string) (int, *ContactListResult, error) {
+	return c.CreateContext(context.Background(), name, description, keyID, keyValues)
+}
+
+// CreateContext creates a list holding the contacts identified by keyValues
+// and returns its id.
+func (c *ContactLists) CreateContext(ctx context.Context, name, description string, keyID int, keyValues []string) (int, *ContactListResult, error) {
+	type request struct {
+		KeyID       string   `json:"key_id"`
+		Name        string   `json:"name"`
+		Description string   `json:"description,omitempty"`
+		ExternalIDs []string `json:"external_ids"`
+	}
+
+	data, err := json.Marshal(&request{
+		KeyID:       strconv.Itoa(keyID),
+		Name:        name,
+		Description: description,
+		ExternalIDs: keyValues,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	r := &Request{
+		Path:   "/v2/contactlist",
+		Method: requestPost,
+		Body:   data,
+	}
+
+	var res struct {
+		ReplyCode int    `json:"replyCode"`
+		ReplyText string `json:"replyText"`
+		Data      struct {
+			ID     flexibleID      `json:"id"`
+			Errors json.RawMessage `json:"errors"`
+		} `json:"data"`
+	}
+
+	if err := sendJSON(ctx, c.client, r, &res); err != nil {
+		return 0, nil, err
+	}
+
+	id, err := strconv.Atoi(string(res.Data.ID))
+	if err != nil {
+		return 0, nil, fmt.Errorf("cannot parse contact list id '%s': %w", res.Data.ID, err)
+	}
+
+	result, err := newContactListResult(len(keyValues), res.Data.Errors, r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return id, result, nil
+}
+
+func (c *ContactLists) AddContacts(listID, keyID int, keyValues []string) (*ContactListResult, error) {
+	return c.AddContactsContext(context.Background(), listID, keyID, keyValues)
+}
+
+func (c *ContactLists) AddContactsContext(ctx context.Context, listID, keyID int, keyValues []string) (*ContactListResult, error) {
+	return c.changeContacts(ctx, fmt.Sprintf("/v2/contactlist/%d/add", listID), "inserted_contacts", keyID, keyValues)
+}
+
+func (c *ContactLists) RemoveContacts(listID, keyID int, keyValues []string) (*ContactListResult, error) {
+	return c.RemoveContactsContext(context.Background(), listID, keyID, keyValues)
+}
+
+func (c *ContactLists) RemoveContactsContext(ctx context.Context, listID, keyID int, keyValues []string) (*ContactListResult, error) {
+	return c.changeContacts(ctx, fmt.Sprintf("/v2/contactlist/%d/delete", listID), "deleted_contacts", keyID, keyValues)
+}
+
+func (c *ContactLists) Delete(listID int) error {
+	return c.DeleteContext(context.Background(), listID)
+}
+
+// DeleteContext deletes the list itself, the contacts are kept.
+func (c *ContactLists) DeleteContext(ctx context.Context, listID int) error {
+	r := &Request{
+		Path:   fmt.Sprintf("/v2/contactlist/%d/deletelist", listID),
+		Method: requestPost,
+	}
+
+	var res struct {
+		ReplyCode int    `json:"replyCode"`
+		ReplyText string `json:"replyText"`
+	}
+
+	return sendJSON(ctx, c.client, r, &res)
+}
+
+// ContactIDs returns an iterator over the internal ids of the contacts in a
+// list. pageSize of 0 uses the default page size.
+func (c *ContactLists) ContactIDs(ctx context.Context, listID, pageSize int) *ContactIDIterator {
+	if pageSize <= 0 {
+		pageSize = defaultContactListPageSize
+	}
+
+	return &ContactIDIterator{
+		ctx:      ctx,
+		lists:    c,
+		listID:   listID,
+		pageSize: pageSize,
+	}
+}
+
+func (c *ContactLists) changeContacts(ctx context.Context, path, countField string, keyID int, keyValues []string) (*ContactListResult, error) {
+	type request struct {
+		KeyID       string   `json:"key_id"`
+		ExternalIDs []string `json:"external_ids"`
+	}
+
+	data, err := json.Marshal(&request{
+		KeyID:       strconv.Itoa(keyID),
+		ExternalIDs: keyValues,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Request{
+		Path:   path,
+		Method: requestPost,
+		Body:   data,
+	}
+
+	var res struct {
+		ReplyCode int                        `json:"replyCode"`
+		ReplyText string                     `json:"replyText"`
+		Data      map[string]json.RawMessage `json:"data"`
+	}
+
+	if err := sendJSON(ctx, c.client, r, &res); err != nil {
+		return nil, err
+	}
+
+	result, err := newContactListResult(0, res.Data["errors"], r)
+	if err != nil {
+		return nil, err
+	}
+
+	if count, ok := res.Data[countField]; ok {
+		if err := json.Unmarshal(count, &result.Count); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func newContactListResult(count int, errorsContent json.RawMessage, r *Request) (*ContactListResult, error) {
+	contactErrors, err := parseContactErrors(errorsContent, r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ContactListResult{
+		Count:  count - len(contactErrors),
+		Errors: make(map[string]error, len(contactErrors)),
+	}
+
+	if result.Count < 0 {
+		result.Count = 0
+	}
+
+	for key, contactErr := range contactErrors {
+		result.Errors[key] = contactErr
+	}
+
+	return result, nil
+}
+
+// ContactIDIterator pages through the contact ids of a list:
+//
+//	it := lists.ContactIDs(ctx, listID, 0)
+//	for it.Next() {
+//		id := it.ID()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type ContactIDIterator struct {
+	ctx      context.Context
+	lists    *ContactLists
+	listID   int
+	pageSize int
+
+	page      []string
+	pos       int
+	skipToken string
+	done      bool
+	err       error
+}
+
+// Next advances to the next id, fetching the next page when needed.
+func (it *ContactIDIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.pos++
+
+	for it.pos >= len(it.page) {
+		if it.done {
+			return false
+		}
+
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	return true
+}
+
+// ID returns the current contact id.
+func (it *ContactIDIterator) ID() string {
+	if it.pos < 0 || it.pos >= len(it.page) {
+		return ""
+	}
+
+	return it.page[it.pos]
+}
+
+func (it *ContactIDIterator) Err() error {
+	return it.err
+}
+
+func (it *ContactIDIterator) fetch() error {
+	query := url.Values{}
+	query.Set(contactListTop, strconv.Itoa(it.pageSize))
+	if it.skipToken != "" {
+		query.Set(contactListSkipToken, it.skipToken)
+	}
+
+	path := &url.URL{
+		Path:     fmt.Sprintf("/v2/contactlist/%d/contactIds", it.listID),
+		RawQuery: query.Encode(),
+	}
+
+	r := &Request{
+		Path:   path.String(),
+		Method: requestGet,
+	}
+
+	var res struct {
+		ReplyCode int    `json:"replyCode"`
+		ReplyText string `json:"replyText"`
+		Data      struct {
+			Value []flexibleID `json:"value"`
+			Next  *string      `json:"next"`
+		} `json:"data"`
+	}
+
+	if err := sendJSON(it.ctx, it.lists.client, r, &res); err != nil {
+		return err
+	}
+
+	it.page = make([]string, len(res.Data.Value))
+	for i, id := range res.Data.Value {
+		it.page[i] = string(id)
+	}
+	it.pos = 0
+
+	it.skipToken = ""
+	if res.Data.Next != nil {
+		if next, err := url.Parse(*res.Data.Next); err == nil {
+			it.skipToken = next.Query().Get(contactListSkipToken)
+		}
+	}
+
+	it.done = it.skipToken == ""
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package gomarsys
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContactLists_List(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/contactlist")
+		assert.Equal(t, req.Method, RequestMethod(requestGet))
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":[{"id":"751827140","name":"Newsletter","created":"2020-01-01 10:00:00","type":0}]}`), nil)
+
+	lists := NewContactLists(client)
+	result, err := lists.List()
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, ContactList{ID: 751827140, Name: "Newsletter", Created: "2020-01-01 10:00:00"}, result[0])
+}
+
+func TestContactLists_Create(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/contactlist")
+		assert.Equal(t, req.Method, RequestMethod(requestPost))
+
+		var v map[string]interface{}
+		require.NoError(t, json.Unmarshal(req.Body, &v))
+		assert.Equal(t, "3", v["key_id"])
+		assert.Equal(t, "Newsletter", v["name"])
+		assert.Len(t, v["external_ids"], 2)
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"id":751827140,"errors":{"b@test.ru":{"2008":"No contact found with the external id: 3"}}}}`), nil)
+
+	lists := NewContactLists(client)
+	id, result, err := lists.Create("Newsletter", "", EMail, []string{"a@test.ru", "b@test.ru"})
+	require.NoError(t, err)
+	assert.Equal(t, 751827140, id)
+	assert.Equal(t, 1, result.Count)
+	assert.True(t, errors.Is(result.Errors["b@test.ru"], ErrContactNotFound))
+}
+
+func TestContactLists_AddContacts(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/contactlist/123/add")
+		assert.Equal(t, req.Method, RequestMethod(requestPost))
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"inserted_contacts":2,"errors":[]}}`), nil)
+
+	lists := NewContactLists(client)
+	result, err := lists.AddContacts(123, EMail, []string{"a@test.ru", "b@test.ru"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Count)
+	assert.Empty(t, result.Errors)
+}
+
+func TestContactLists_RemoveContacts(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/contactlist/123/delete")
+		assert.Equal(t, req.Method, RequestMethod(requestPost))
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"deleted_contacts":1,"errors":[]}}`), nil)
+
+	lists := NewContactLists(client)
+	result, err := lists.RemoveContacts(123, EMail, []string{"a@test.ru"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Count)
+}
+
+func TestContactLists_Delete(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/contactlist/123/deletelist")
+		assert.Equal(t, req.Method, RequestMethod(requestPost))
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{}}`), nil)
+
+	lists := NewContactLists(client)
+	require.NoError(t, lists.Delete(123))
+}
+
+func TestContactLists_ContactIDs(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/contactlist/123/contactIds?%24top=2"
+	})).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"value":["1","2"],"next":"/api/v2/contactlist/123/contactIds?$top=2&$skiptoken=abc"}}`), nil)
+	client.(*ClientMock).On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/contactlist/123/contactIds?%24skiptoken=abc&%24top=2"
+	})).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"value":[3],"next":null}}`), nil)
+
+	lists := NewContactLists(client)
+	it := lists.ContactIDs(context.Background(), 123, 2)
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.ID())
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"1", "2", "3"}, ids)
+}
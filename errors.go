@@ -0,0 +1,159 @@
+package gomarsys
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// maxErrorBodySize limits how much of an error response is kept in APIError.
+const maxErrorBodySize = 64 * 1024
+
+// Emarsys reply codes handled by this package, returned either as the
+// replyCode of a response or as the code of a per-contact error. Other codes
+// are still available from APIError.ReplyCode and UserError.Code.
+const (
+	ErrorCodeInvalidKeyField    = 2004
+	ErrorCodeMissingKeyValue    = 2005
+	ErrorCodeInvalidFieldID     = 2006
+	ErrorCodeInvalidFieldValue  = 2007
+	ErrorCodeContactNotFound    = 2008
+	ErrorCodeDuplicateContact   = 2009
+	ErrorCodeAmbiguousContact   = 2010
+	ErrorCodeInvalidContactList = 3004
+	ErrorCodeInvalidExportID    = 10001
+)
+
+// Sentinel errors matching the reply codes above. An error returned by any
+// service of this package matches the sentinel of its reply code with
+// errors.Is, whether the code came with an error status, with a 200 response
+// or as a per-contact error.
+var (
+	ErrInvalidKeyField    = newReplyCodeError(ErrorCodeInvalidKeyField, "invalid key field")
+	ErrMissingKeyValue    = newReplyCodeError(ErrorCodeMissingKeyValue, "no value provided for key field")
+	ErrInvalidFieldID     = newReplyCodeError(ErrorCodeInvalidFieldID, "invalid field id")
+	ErrInvalidFieldValue  = newReplyCodeError(ErrorCodeInvalidFieldValue, "invalid field value")
+	ErrContactNotFound    = newReplyCodeError(ErrorCodeContactNotFound, "contact not found")
+	ErrDuplicateContact   = newReplyCodeError(ErrorCodeDuplicateContact, "contact already exists")
+	ErrAmbiguousContact   = newReplyCodeError(ErrorCodeAmbiguousContact, "more than one contact found")
+	ErrInvalidContactList = newReplyCodeError(ErrorCodeInvalidContactList, "invalid contact list")
+	ErrInvalidExportID    = newReplyCodeError(ErrorCodeInvalidExportID, "invalid export id")
+)
+
+// Sentinel errors matching the HTTP status of an error response, whatever
+// its reply code: authentication failures and exceeded rate limits or quotas.
+var (
+	ErrUnauthorized = newStatusError(http.StatusUnauthorized, "authentication failed")
+	ErrForbidden    = newStatusError(http.StatusForbidden, "access denied")
+	ErrRateLimited  = newStatusError(http.StatusTooManyRequests, "rate limit exceeded")
+)
+
+var replyCodeErrors = map[int]error{}
+
+type replyCodeError struct {
+	code    int
+	message string
+}
+
+func (e *replyCodeError) Error() string {
+	return e.message
+}
+
+func newReplyCodeError(code int, message string) error {
+	err := &replyCodeError{code: code, message: message}
+	replyCodeErrors[code] = err
+
+	return err
+}
+
+// ReplyCodeError returns the sentinel error for a reply code, or nil if the
+// code is not known.
+func ReplyCodeError(code int) error {
+	return replyCodeErrors[code]
+}
+
+type statusError struct {
+	status  int
+	message string
+}
+
+func (e *statusError) Error() string {
+	return e.message
+}
+
+func newStatusError(status int, message string) error {
+	return &statusError{status: status, message: message}
+}
+
+func matchStatus(status int, target error) bool {
+	t, ok := target.(*statusError)
+
+	return ok && t.status == status
+}
+
+func matchReplyCode(code int, target error) bool {
+	t, ok := target.(*replyCodeError)
+
+	return ok && code != 0 && t.code == code
+}
+
+// APIError is returned when Emarsys answers with a non-200 status or a 200
+// response carrying a non-zero replyCode. Users wraps it in *UserError, which
+// also wraps an APIError for every per-contact error of a response.
+type APIError struct {
+	StatusCode int
+	ReplyCode  int
+	ReplyText  string
+	Body       []byte
+	Path       string
+	Method     string
+	// Header holds the response headers, if the error came with a response.
+	Header http.Header
+}
+
+func (e *APIError) Error() string {
+	if e.ReplyText != "" || e.ReplyCode != 0 {
+		return fmt.Sprintf("error response, code: %d, reply code: %d, reply text: '%s'", e.StatusCode, e.ReplyCode, e.ReplyText)
+	}
+
+	return fmt.Sprintf("error response, code: %d", e.StatusCode)
+}
+
+func (e *APIError) Is(target error) bool {
+	return matchReplyCode(e.ReplyCode, target) || matchStatus(e.StatusCode, target)
+}
+
+func newAPIError(statusCode int, body []byte, r *Request) *APIError {
+	apiError := &APIError{
+		StatusCode: statusCode,
+		Body:       body,
+		Path:       r.Path,
+		Method:     r.Method.String(),
+	}
+
+	var reply struct {
+		ReplyCode int    `json:"replyCode"`
+		ReplyText string `json:"replyText"`
+	}
+
+	if err := json.Unmarshal(body, &reply); err == nil {
+		apiError.ReplyCode = reply.ReplyCode
+		apiError.ReplyText = reply.ReplyText
+	}
+
+	return apiError
+}
+
+// checkReplyCode returns an *APIError when a 200 response carries a non-zero
+// replyCode.
+func checkReplyCode(response []byte, r *Request) *APIError {
+	var reply struct {
+		ReplyCode int `json:"replyCode"`
+	}
+
+	if err := json.Unmarshal(response, &reply); err != nil || reply.ReplyCode == 0 {
+		return nil
+	}
+
+	return newAPIError(http.StatusOK, response, r)
+}
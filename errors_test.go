@@ -0,0 +1,103 @@
+package gomarsys
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &APIError{StatusCode: http.StatusBadRequest, ReplyCode: ErrorCodeContactNotFound})
+
+	assert.True(t, errors.Is(err, ErrContactNotFound))
+	assert.False(t, errors.Is(err, ErrDuplicateContact))
+	assert.False(t, errors.Is(&APIError{StatusCode: http.StatusInternalServerError}, ErrContactNotFound))
+}
+
+func TestAPIError_IsStatus(t *testing.T) {
+	err := &APIError{StatusCode: http.StatusUnauthorized, ReplyCode: 1, ReplyText: "Unauthorized"}
+
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+	assert.False(t, errors.Is(err, ErrRateLimited))
+	assert.True(t, errors.Is(&APIError{StatusCode: http.StatusTooManyRequests}, ErrRateLimited))
+	assert.True(t, errors.Is(&APIError{StatusCode: http.StatusForbidden}, ErrForbidden))
+	assert.False(t, errors.Is(&APIError{StatusCode: http.StatusOK, ReplyCode: 1}, ErrUnauthorized))
+
+	userError := newRequestError(err)
+	assert.True(t, errors.Is(userError, ErrUnauthorized))
+}
+
+func TestUserError_Is(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).
+		Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"errors":{"":{"2005":"No value provided for key field: 3"}},"deleted_contacts":0}}`), nil)
+
+	user := NewUsers(client)
+	err := user.Delete(EMail, "")
+	require.Error(t, err)
+
+	assert.True(t, errors.Is(err, ErrMissingKeyValue))
+	assert.False(t, errors.Is(err, ErrContactNotFound))
+
+	var apiError *APIError
+	require.True(t, errors.As(err, &apiError))
+	assert.Equal(t, ErrorCodeMissingKeyValue, apiError.ReplyCode)
+	assert.Equal(t, "/v2/contact/delete", apiError.Path)
+}
+
+func TestReplyCode_OKStatus(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).
+		Return([]byte(`{"replyCode":10001,"replyText":"Invalid export id","data":""}`), nil)
+
+	_, err := NewExport(client).CheckStatus(1)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidExportID))
+
+	var apiError *APIError
+	require.True(t, errors.As(err, &apiError))
+	assert.Equal(t, http.StatusOK, apiError.StatusCode)
+
+	_, err = NewUsers(client).GetSegment(SegmentRequest{})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidExportID))
+
+	var userError *UserError
+	require.True(t, errors.As(err, &userError))
+	assert.Equal(t, ErrorCodeInvalidExportID, userError.Code())
+
+	err = NewExternalEvents(client).TriggerEvent(1, nil)
+	assert.True(t, errors.Is(err, ErrInvalidExportID))
+}
+
+func TestUsers_StatusErrorWrapped(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusBadRequest)
+		_, _ = rw.Write([]byte(`{"replyCode":2008,"replyText":"No contact found with the external id: 3","data":""}`))
+	}))
+	defer server.Close()
+
+	user := NewUsers(NewClient("test", "test", WithCustomHost(server.URL+"/")))
+	_, err := user.GetUserInfo(EMail, "a@test.ru", []int{EMail})
+	require.Error(t, err)
+
+	var userError *UserError
+	require.True(t, errors.As(err, &userError))
+	assert.Equal(t, ErrorCodeContactNotFound, userError.Code())
+	assert.True(t, errors.Is(err, ErrContactNotFound))
+
+	var apiError *APIError
+	require.True(t, errors.As(err, &apiError))
+	assert.Equal(t, http.StatusBadRequest, apiError.StatusCode)
+}
+
+func TestReplyCodeError(t *testing.T) {
+	assert.Equal(t, ErrContactNotFound, ReplyCodeError(ErrorCodeContactNotFound))
+	assert.Nil(t, ReplyCodeError(1))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -11,7 +12,8 @@ import (
 )
 
 type Export struct {
-	client ClientInterface
+	client       ClientInterface
+	statusPeriod time.Duration
 }
 
 const (
@@ -42,45 +44,75 @@ type ExportStatus struct {
 
 func NewExport(client ClientInterface) *Export {
 	return &Export{
-		client: client,
+		client:       client,
+		statusPeriod: emarsysUpdateStatusPeriod,
 	}
 }
 
-func (e *Export) WaitExportComplete(ctx context.Context, jobID int) (*ExportStatus, error) {
-	var (
-		status *ExportStatus
-		err    error
-	)
+// WaitExportComplete polls the job status until the job is done. A job ending
+// in the error state is reported as an *ExportJobError.
+func (e *Export) WaitExportComplete(ctx context.Context, jobID int, opts ...WaitOptions) (*ExportStatus, error) {
+	return e.waitExport(ctx, jobID, e.newWaitOptions(opts))
+}
+
+func (e *Export) waitExport(ctx context.Context, jobID int, o *waitOptions) (*ExportStatus, error) {
+	waitCtx := ctx
+	if o.maxWait > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, o.maxWait)
+		defer cancel()
+	}
 
-	t := time.NewTicker(emarsysUpdateStatusPeriod)
-	defer t.Stop()
+	var previous *ExportStatus
+
+	// only the expiry of maxWait is an ExportWaitError, not the caller's
+	// context ending
+	waitError := func(err error) error {
+		if o.maxWait > 0 && waitCtx.Err() != nil && ctx.Err() == nil {
+			return &ExportWaitError{JobID: jobID, Status: previous, err: waitCtx.Err()}
+		}
+		return err
+	}
+
+	delay := o.initialDelay
+	interval := o.interval
 
 	for {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-t.C:
-			break
+		if err := sleepContext(waitCtx, delay); err != nil {
+			return nil, waitError(err)
 		}
 
-		status, err = e.CheckStatus(jobID)
+		status, err := e.CheckStatusContext(waitCtx, jobID)
 		if err != nil {
-			return nil, err
+			return nil, waitError(err)
 		}
 
-		if status.Data.Status == ExportStatusDone || status.Data.Status == ExportStatusError {
-			break
+		if o.onStatus != nil {
+			o.onStatus(status)
 		}
-	}
 
-	if status == nil {
-		return nil, fmt.Errorf("unknown export status, job id: %d", jobID)
-	}
+		if o.onChange != nil && (previous == nil || previous.Data.Status != status.Data.Status) {
+			o.onChange(previous, status)
+		}
 
-	return status, nil
+		switch status.Data.Status {
+		case ExportStatusDone:
+			return status, nil
+		case ExportStatusError:
+			return nil, &ExportJobError{JobID: jobID, Status: status}
+		}
+
+		previous = status
+		delay = interval
+		interval = o.next(interval)
+	}
 }
 
 func (e *Export) CheckStatus(id int) (*ExportStatus, error) {
+	return e.CheckStatusContext(context.Background(), id)
+}
+
+func (e *Export) CheckStatusContext(ctx context.Context, id int) (*ExportStatus, error) {
 	r := &Request{
 		Path:   fmt.Sprintf("/v2/export/%d", id),
 		Method: requestGet,
@@ -88,9 +120,12 @@ func (e *Export) CheckStatus(id int) (*ExportStatus, error) {
 
 	status := &ExportStatus{}
 
-	if response, err := e.client.Send(r); err != nil {
+	if response, err := e.client.SendContext(ctx, r); err != nil {
 		return nil, err
 	} else {
+		if apiError := checkReplyCode(response, r); apiError != nil {
+			return nil, apiError
+		}
 		err := json.Unmarshal(response, status)
 		if err != nil {
 			return nil, err
@@ -101,12 +136,16 @@ func (e *Export) CheckStatus(id int) (*ExportStatus, error) {
 }
 
 func (e *Export) DownloadExportData(id int) ([][]string, error) {
+	return e.DownloadExportDataContext(context.Background(), id)
+}
+
+func (e *Export) DownloadExportDataContext(ctx context.Context, id int) ([][]string, error) {
 	r := &Request{
 		Path:   fmt.Sprintf("/v2/export/%d/data", id),
 		Method: requestGet,
 	}
 
-	if response, err := e.client.Send(r); err != nil {
+	if response, err := e.client.SendContext(ctx, r); err != nil {
 		return nil, err
 	} else {
 		r := csv.NewReader(strings.NewReader(string(response)))
@@ -119,37 +158,62 @@ func (e *Export) DownloadExportData(id int) ([][]string, error) {
 	}
 }
 
-func (e *Export) copyIO(source io.Reader, destination io.Writer) error {
+// copyIO copies source to destination and returns the number of bytes written.
+// Errors from destination are wrapped in *writeError.
+func (e *Export) copyIO(source io.Reader, destination io.Writer) (int64, error) {
 	buf := make([]byte, bufferSize)
 
+	var written int64
+
 	for {
 		n, err := source.Read(buf)
-		if err != nil && err != io.EOF {
-			return err
-		}
-		if n == 0 {
-			break
+		if n > 0 {
+			m, werr := destination.Write(buf[:n])
+			written += int64(m)
+			if werr != nil {
+				return written, &writeError{werr}
+			}
 		}
 
-		if _, err := destination.Write(buf[:n]); err != nil {
-			return err
+		if err == io.EOF {
+			return written, nil
+		}
+		if err != nil {
+			return written, err
 		}
 	}
+}
 
-	return nil
+func (e *Export) DownloadExportToIO(id int, stream io.Writer, opts ...DownloadOptions) error {
+	return e.DownloadExportToIOContext(context.Background(), id, stream, opts...)
 }
 
-func (e *Export) DownloadExportToIO(id int, stream io.Writer) error {
-	r := &Request{
-		Path:   fmt.Sprintf("/v2/export/%d/data", id),
-		Method: requestGet,
-	}
+// DownloadExportToIOContext writes the export data to stream. With
+// WithDownloadOffset the download starts at the given byte, with
+// WithDownloadResume an interrupted download continues from the last byte
+// written.
+func (e *Export) DownloadExportToIOContext(ctx context.Context, id int, stream io.Writer, opts ...DownloadOptions) error {
+	o := newDownloadOptions(opts)
+	offset := o.offset
+
+	for attempt := 1; ; attempt++ {
+		n, err := e.downloadRange(ctx, id, stream, offset)
+		offset += n
+		if err == nil {
+			return nil
+		}
 
-	if responseStream, err := e.client.SendIO(r); err != nil {
-		return err
-	} else {
-		defer func() { _ = responseStream.Close() }()
+		var we *writeError
+		if errors.As(err, &we) {
+			return we.err
+		}
 
-		return e.copyIO(responseStream, stream)
+		if o.resume == nil || ctx.Err() != nil || attempt >= o.resume.MaxAttempts || !isResumable(err) {
+			return err
+		}
+
+		if err := sleepContext(ctx, o.resume.backoff(attempt, nil)); err != nil {
+			return err
+		}
 	}
 }
@@ -0,0 +1,105 @@
+package gomarsys
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ExportDecoder maps the columns of an export onto field ids using its header
+// row, so rows decode into the same User shape GetUserInfo returns.
+type ExportDecoder struct {
+	rows     *ExportRows
+	codec    *fieldCodec
+	columns  []int
+	idColumn int
+}
+
+// NewExportDecoder builds a decoder for rows, which must have been requested
+// with AddFieldNamesHeader. Column names are resolved through the field
+// registry of Users, or the system field names if it has none; the choice
+// translator of Users serves the ",label" tag option in Decode. Columns which
+// cannot be resolved are skipped.
+func (u *Users) NewExportDecoder(ctx context.Context, rows *ExportRows) (*ExportDecoder, error) {
+	header := rows.Header()
+	if header == nil {
+		return nil, fmt.Errorf("export has no header row")
+	}
+
+	codec, err := u.fieldCodec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &ExportDecoder{
+		rows:     rows,
+		codec:    codec,
+		columns:  make([]int, len(header)),
+		idColumn: -1,
+	}
+
+	for i, name := range header {
+		d.columns[i] = -1
+
+		switch strings.ToLower(name) {
+		case "id", "user_id", "contact_id":
+			d.idColumn = i
+			continue
+		}
+
+		if id, ok := resolveColumn(name, codec.resolve); ok {
+			d.columns[i] = id
+		}
+	}
+
+	return d, nil
+}
+
+// Next advances the underlying rows, see ExportRows.Next.
+func (d *ExportDecoder) Next() bool {
+	return d.rows.Next()
+}
+
+func (d *ExportDecoder) Err() error {
+	return d.rows.Err()
+}
+
+// User returns the current row as a User.
+func (d *ExportDecoder) User() *User {
+	row := d.rows.Row()
+	user := &User{Data: make(map[int]string, len(row))}
+
+	for i, value := range row {
+		if i == d.idColumn {
+			user.ID = value
+			continue
+		}
+
+		if i < len(d.columns) && d.columns[i] >= 0 {
+			user.Data[d.columns[i]] = value
+		}
+	}
+
+	return user
+}
+
+// Decode fills a struct with `emarsys` tags from the current row, see
+// Unmarshal.
+func (d *ExportDecoder) Decode(v interface{}) error {
+	return unmarshal(d.User().Data, v, d.codec)
+}
+
+// resolveColumn resolves a header column, which holds a field id, string id
+// or name such as "First Name".
+func resolveColumn(name string, resolve fieldResolver) (int, bool) {
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, true
+	}
+
+	if id, ok := resolve(name); ok {
+		return id, true
+	}
+
+	return resolve(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_"))
+}
@@ -0,0 +1,66 @@
+package gomarsys
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportDecoder_User(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("SendIO", mock.Anything).
+		Return(NewReadCloser([]byte("user_id,Email,First Name,Date of birth,Unknown\n123,a@test.ru,A,1990-05-17,x\n")), nil)
+
+	export := NewExport(client)
+	rows, err := export.Rows(1, BaseExportRequest{Delimiter: ",", AddFieldNamesHeader: 1})
+	require.NoError(t, err)
+
+	decoder, err := NewUsers(client).NewExportDecoder(context.Background(), rows)
+	require.NoError(t, err)
+
+	require.True(t, decoder.Next())
+	assert.Equal(t, &User{
+		ID: "123",
+		Data: map[int]string{
+			EMail:     "a@test.ru",
+			FirstName: "A",
+		},
+	}, decoder.User())
+	assert.False(t, decoder.Next())
+	require.NoError(t, decoder.Err())
+}
+
+func TestExportDecoder_Decode(t *testing.T) {
+	client := newTestChoicesClient()
+	client.On("SendIO", mock.Anything).
+		Return(NewReadCloser([]byte("user_id;email;Gender;Favourite brands;4\n123;a@test.ru;2;1,3;1990-05-17\n")), nil)
+
+	translator := NewChoiceTranslator(NewFieldRegistry(NewFields(client)))
+	users := NewUsers(client, WithChoiceTranslator(translator))
+
+	export := NewExport(client)
+	rows, err := export.Rows(1, BaseExportRequest{Delimiter: ";", AddFieldNamesHeader: 1})
+	require.NoError(t, err)
+
+	decoder, err := users.NewExportDecoder(context.Background(), rows)
+	require.NoError(t, err)
+
+	var contact struct {
+		Email     string    `emarsys:"email"`
+		Gender    string    `emarsys:"gender,label"`
+		Brands    []string  `emarsys:"favourite_brands,label"`
+		BirthDate time.Time `emarsys:"birth_date"`
+	}
+
+	require.True(t, decoder.Next())
+	require.NoError(t, decoder.Decode(&contact))
+
+	assert.Equal(t, "a@test.ru", contact.Email)
+	assert.Equal(t, "Female", contact.Gender)
+	assert.Equal(t, []string{"Acme", "Initech"}, contact.Brands)
+	assert.Equal(t, time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC), contact.BirthDate)
+}
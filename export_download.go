@@ -0,0 +1,218 @@
+package gomarsys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type DownloadOptions func(o *downloadOptions)
+
+type downloadOptions struct {
+	offset int64
+	resume *RetryPolicy
+}
+
+// DownloadSizeError is returned when the downloaded data does not match the
+// total size announced by the server.
+type DownloadSizeError struct {
+	Expected int64
+	Received int64
+}
+
+func (e *DownloadSizeError) Error() string {
+	return fmt.Sprintf("export download size mismatch: expected %d bytes, received %d", e.Expected, e.Received)
+}
+
+// ContentRangeError is returned when the Content-Range of a response cannot
+// be parsed or does not start at the requested offset.
+type ContentRangeError struct {
+	Value  string
+	Offset int64
+}
+
+func (e *ContentRangeError) Error() string {
+	return fmt.Sprintf("unexpected content range %q for offset %d", e.Value, e.Offset)
+}
+
+type writeError struct {
+	err error
+}
+
+func (e *writeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *writeError) Unwrap() error {
+	return e.err
+}
+
+// WithDownloadOffset starts the download at the given byte, e.g. to append to
+// a partially written file.
+func WithDownloadOffset(offset int64) DownloadOptions {
+	return func(o *downloadOptions) {
+		o.offset = offset
+	}
+}
+
+// WithDownloadResume re-requests the remaining data after a dropped connection
+// or a truncated response, up to policy.MaxAttempts requests in total.
+func WithDownloadResume(policy RetryPolicy) DownloadOptions {
+	return func(o *downloadOptions) {
+		if policy.MaxAttempts < 1 {
+			policy.MaxAttempts = 1
+		}
+		if policy.MaxBackoff < policy.MinBackoff {
+			policy.MaxBackoff = policy.MinBackoff
+		}
+
+		o.resume = &policy
+	}
+}
+
+func newDownloadOptions(opts []DownloadOptions) *downloadOptions {
+	o := &downloadOptions{}
+	for _, f := range opts {
+		f(o)
+	}
+
+	return o
+}
+
+// downloadRange writes the export data starting at offset to stream and
+// returns the number of bytes written.
+func (e *Export) downloadRange(ctx context.Context, id int, stream io.Writer, offset int64) (int64, error) {
+	r := &Request{
+		Path:   fmt.Sprintf("/v2/export/%d/data", id),
+		Method: requestGet,
+	}
+
+	if offset > 0 {
+		r.Header = http.Header{"Range": []string{fmt.Sprintf("bytes=%d-", offset)}}
+	}
+
+	responseStream, err := e.client.SendIOContext(ctx, r)
+	if err != nil {
+		if offset > 0 && rangeComplete(err, offset) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	defer func() { _ = responseStream.Close() }()
+
+	total := int64(-1)
+
+	if body, ok := responseStream.(*ResponseBody); ok {
+		switch body.StatusCode {
+		case http.StatusPartialContent:
+			value := body.Header.Get("Content-Range")
+			start, size, err := parseContentRange(value)
+			if err != nil || start != offset {
+				return 0, &ContentRangeError{Value: value, Offset: offset}
+			}
+			total = size
+		case http.StatusOK:
+			// the server ignored the range, skip the data already written
+			if offset > 0 {
+				if _, err := io.CopyN(ioutil.Discard, responseStream, offset); err != nil {
+					return 0, err
+				}
+			}
+			if length := body.Header.Get("Content-Length"); length != "" {
+				if size, err := strconv.ParseInt(length, 10, 64); err == nil {
+					total = size
+				}
+			}
+		}
+	}
+
+	n, err := e.copyIO(responseStream, stream)
+	if err != nil {
+		return n, err
+	}
+
+	if total >= 0 && offset+n != total {
+		return n, &DownloadSizeError{Expected: total, Received: offset + n}
+	}
+
+	return n, nil
+}
+
+// rangeComplete reports whether err is a 416 response telling that offset is
+// already the total size, i.e. everything was written by an earlier attempt.
+func rangeComplete(err error, offset int64) bool {
+	var apiError *APIError
+	if !errors.As(err, &apiError) || apiError.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		return false
+	}
+
+	// unsatisfied ranges are answered with "bytes */total"
+	value := strings.TrimPrefix(apiError.Header.Get("Content-Range"), "bytes */")
+	total, parseErr := strconv.ParseInt(value, 10, 64)
+
+	return parseErr == nil && total == offset
+}
+
+// parseContentRange parses "bytes start-end/total". The total is -1 when the
+// server sends "*".
+func parseContentRange(value string) (int64, int64, error) {
+	invalid := fmt.Errorf("invalid content range: %q", value)
+
+	if !strings.HasPrefix(value, "bytes ") {
+		return 0, 0, invalid
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(value, "bytes "), "/", 2)
+	if len(parts) != 2 {
+		return 0, 0, invalid
+	}
+
+	bounds := strings.SplitN(parts[0], "-", 2)
+	if len(bounds) != 2 {
+		return 0, 0, invalid
+	}
+
+	start, err := strconv.ParseInt(bounds[0], 10, 64)
+	if err != nil {
+		return 0, 0, invalid
+	}
+
+	if parts[1] == "*" {
+		return start, -1, nil
+	}
+
+	total, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, invalid
+	}
+
+	return start, total, nil
+}
+
+// isResumable reports whether a failed download may continue with another
+// range request.
+func isResumable(err error) bool {
+	var sizeError *DownloadSizeError
+	if errors.As(err, &sizeError) {
+		return sizeError.Received < sizeError.Expected
+	}
+
+	var rangeError *ContentRangeError
+	if errors.As(err, &rangeError) {
+		return false
+	}
+
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError.StatusCode == http.StatusTooManyRequests || apiError.StatusCode >= http.StatusInternalServerError
+	}
+
+	// transport errors and errors reading the response body
+	return true
+}
@@ -0,0 +1,149 @@
+package gomarsys
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testExportData = "user_id,3\n1,a@example.com\n2,b@example.com\n3,c@example.com\n"
+
+func TestExport_DownloadExportToIOResume(t *testing.T) {
+	var requests int32
+	var ranges []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/v2/export/1/data/", req.URL.Path)
+		ranges = append(ranges, req.Header.Get("Range"))
+
+		if atomic.AddInt32(&requests, 1) == 1 {
+			// drop the connection halfway through the body
+			rw.Header().Set("Content-Length", fmt.Sprint(len(testExportData)))
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(testExportData[:20]))
+			return
+		}
+
+		http.ServeContent(rw, req, "", time.Time{}, strings.NewReader(testExportData))
+	}))
+	defer server.Close()
+
+	export := NewExport(NewClient("test", "test", WithCustomHost(server.URL+"/")))
+
+	var b bytes.Buffer
+	err := export.DownloadExportToIO(1, &b, WithDownloadResume(RetryPolicy{MaxAttempts: 3}))
+	require.NoError(t, err)
+	assert.Equal(t, testExportData, b.String())
+	assert.Equal(t, []string{"", "bytes=20-"}, ranges)
+}
+
+func TestExport_DownloadExportToIOOffset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "bytes=10-", req.Header.Get("Range"))
+		http.ServeContent(rw, req, "", time.Time{}, strings.NewReader(testExportData))
+	}))
+	defer server.Close()
+
+	export := NewExport(NewClient("test", "test", WithCustomHost(server.URL+"/")))
+
+	var b bytes.Buffer
+	err := export.DownloadExportToIO(1, &b, WithDownloadOffset(10))
+	require.NoError(t, err)
+	assert.Equal(t, testExportData[10:], b.String())
+}
+
+func TestExport_DownloadExportToIORangeIgnored(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(testExportData))
+	}))
+	defer server.Close()
+
+	export := NewExport(NewClient("test", "test", WithCustomHost(server.URL+"/")))
+
+	var b bytes.Buffer
+	err := export.DownloadExportToIO(1, &b, WithDownloadOffset(10))
+	require.NoError(t, err)
+	assert.Equal(t, testExportData[10:], b.String())
+}
+
+func TestExport_DownloadExportToIOSizeMismatch(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("SendIO", mock.Anything).Return(&ResponseBody{
+		ReadCloser: NewReadCloser([]byte(testExportData[:20])),
+		StatusCode: http.StatusPartialContent,
+		Header:     http.Header{"Content-Range": []string{fmt.Sprintf("bytes 0-%d/%d", len(testExportData)-1, len(testExportData))}},
+	}, nil)
+
+	export := NewExport(client)
+
+	var b bytes.Buffer
+	err := export.DownloadExportToIO(1, &b)
+	require.Error(t, err)
+
+	var sizeError *DownloadSizeError
+	require.True(t, errors.As(err, &sizeError))
+	assert.Equal(t, int64(len(testExportData)), sizeError.Expected)
+	assert.Equal(t, int64(20), sizeError.Received)
+}
+
+func TestExport_DownloadExportToIORangeComplete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		http.ServeContent(rw, req, "", time.Time{}, strings.NewReader(testExportData))
+	}))
+	defer server.Close()
+
+	export := NewExport(NewClient("test", "test", WithCustomHost(server.URL+"/")))
+
+	var b bytes.Buffer
+	err := export.DownloadExportToIO(1, &b, WithDownloadOffset(int64(len(testExportData))))
+	require.NoError(t, err)
+	assert.Empty(t, b.String())
+
+	err = export.DownloadExportToIO(1, &b, WithDownloadOffset(int64(len(testExportData)+1)))
+	require.Error(t, err)
+}
+
+func TestExport_DownloadExportToIOInvalidRangeNotResumed(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("SendIO", mock.Anything).Return(&ResponseBody{
+		ReadCloser: NewReadCloser([]byte(testExportData)),
+		StatusCode: http.StatusPartialContent,
+		Header:     http.Header{"Content-Range": []string{"bytes 0-9/100"}},
+	}, nil)
+
+	export := NewExport(client)
+
+	var b bytes.Buffer
+	err := export.DownloadExportToIO(1, &b, WithDownloadOffset(10), WithDownloadResume(RetryPolicy{MaxAttempts: 3}))
+	require.Error(t, err)
+
+	var rangeError *ContentRangeError
+	require.True(t, errors.As(err, &rangeError))
+	assert.Equal(t, int64(10), rangeError.Offset)
+	client.(*ClientMock).AssertNumberOfCalls(t, "SendIO", 1)
+}
+
+func TestParseContentRange(t *testing.T) {
+	start, total, err := parseContentRange("bytes 20-99/100")
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), start)
+	assert.Equal(t, int64(100), total)
+
+	start, total, err = parseContentRange("bytes 20-99/*")
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), start)
+	assert.Equal(t, int64(-1), total)
+
+	_, _, err = parseContentRange("items 0-1/2")
+	assert.Error(t, err)
+}
@@ -0,0 +1,149 @@
+package gomarsys
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ExportJob describes how to start an export. Request is the part of the
+// export request describing the produced file.
+type ExportJob struct {
+	Start   func(ctx context.Context) (*ExportResult, error)
+	Request BaseExportRequest
+}
+
+type ExportPipelineOptions func(o *exportPipelineOptions)
+
+type exportPipelineOptions struct {
+	onStatus    func(status *ExportStatus)
+	cleanup     func()
+	waitOptions []WaitOptions
+}
+
+// WithExportProgress calls f with every status received while waiting for the
+// export.
+func WithExportProgress(f func(status *ExportStatus)) ExportPipelineOptions {
+	return func(o *exportPipelineOptions) {
+		o.onStatus = f
+	}
+}
+
+// WithExportWait configures how the pipeline polls the job status.
+func WithExportWait(waitOptions ...WaitOptions) ExportPipelineOptions {
+	return func(o *exportPipelineOptions) {
+		o.waitOptions = append(o.waitOptions, waitOptions...)
+	}
+}
+
+// WithExportCleanup calls f when the pipeline fails or is cancelled after the
+// export was started, e.g. to remove a partially written file.
+func WithExportCleanup(f func()) ExportPipelineOptions {
+	return func(o *exportPipelineOptions) {
+		o.cleanup = f
+	}
+}
+
+func (u *Users) SegmentExportJob(request SegmentRequest) ExportJob {
+	return ExportJob{
+		Start: func(ctx context.Context) (*ExportResult, error) {
+			return u.GetSegmentContext(ctx, request)
+		},
+		Request: request.BaseExportRequest,
+	}
+}
+
+func (u *Users) ChangesExportJob(request ChangesRequest) ExportJob {
+	return ExportJob{
+		Start: func(ctx context.Context) (*ExportResult, error) {
+			return u.GetChangesContext(ctx, request)
+		},
+		Request: request.BaseExportRequest,
+	}
+}
+
+func (u *Users) ContactsExportJob(request ContactRequest) ExportJob {
+	return ExportJob{
+		Start: func(ctx context.Context) (*ExportResult, error) {
+			return u.GetContactsContext(ctx, request)
+		},
+		Request: request.BaseExportRequest,
+	}
+}
+
+// RunToWriter starts the export, waits for it to complete and streams the
+// result to stream.
+func (e *Export) RunToWriter(ctx context.Context, job ExportJob, stream io.Writer, opts ...ExportPipelineOptions) (*ExportStatus, error) {
+	return e.run(ctx, job, opts, func(jobID int) error {
+		return e.DownloadExportToIOContext(ctx, jobID, stream)
+	})
+}
+
+// RunRows starts the export, waits for it to complete and calls f with every
+// row of the result. The row slice is reused between calls.
+func (e *Export) RunRows(ctx context.Context, job ExportJob, f func(header, row []string) error, opts ...ExportPipelineOptions) (*ExportStatus, error) {
+	return e.run(ctx, job, opts, func(jobID int) error {
+		rows, err := e.RowsContext(ctx, jobID, job.Request)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			if err := f(rows.Header(), rows.Row()); err != nil {
+				return err
+			}
+		}
+
+		return rows.Err()
+	})
+}
+
+func (e *Export) run(ctx context.Context, job ExportJob, opts []ExportPipelineOptions, download func(jobID int) error) (*ExportStatus, error) {
+	o := &exportPipelineOptions{}
+	for _, f := range opts {
+		f(o)
+	}
+
+	result, err := job.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.ReplyCode != 0 {
+		return nil, &APIError{StatusCode: http.StatusOK, ReplyCode: result.ReplyCode, ReplyText: result.ReplyText}
+	}
+
+	if result.Data.ID == 0 {
+		return nil, fmt.Errorf("export not started, no job id returned: '%s'", result.ReplyText)
+	}
+
+	status, err := e.waitAndDownload(ctx, result.Data.ID, o, download)
+	if err != nil {
+		if o.cleanup != nil {
+			o.cleanup()
+		}
+		return nil, err
+	}
+
+	return status, nil
+}
+
+func (e *Export) waitAndDownload(ctx context.Context, jobID int, o *exportPipelineOptions, download func(jobID int) error) (*ExportStatus, error) {
+	w := e.newWaitOptions(o.waitOptions)
+	if o.onStatus != nil {
+		w.onStatus = o.onStatus
+	}
+
+	status, err := e.waitExport(ctx, jobID, w)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := download(jobID); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
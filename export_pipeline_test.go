@@ -0,0 +1,163 @@
+package gomarsys
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPipelineClient(finalStatus string) ClientInterface {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/export/filter"
+	})).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"id":7}}`), nil)
+	client.(*ClientMock).On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/export/7"
+	})).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"id":"7","status":"in_progress"}}`), nil).Once()
+	client.(*ClientMock).On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/export/7"
+	})).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"id":"7","status":"`+finalStatus+`"}}`), nil)
+	client.(*ClientMock).On("SendIO", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/export/7/data"
+	})).Return(NewReadCloser([]byte("user_id,3\n1,a@example.com\n2,b@example.com\n")), nil)
+
+	return client
+}
+
+func newTestPipelineJob(client ClientInterface) ExportJob {
+	return NewUsers(client).SegmentExportJob(SegmentRequest{
+		BaseExportRequest: BaseExportRequest{
+			ContactFields:       []int{3},
+			Delimiter:           ",",
+			AddFieldNamesHeader: 1,
+		},
+		Filter: 100,
+	})
+}
+
+func TestExport_RunToWriter(t *testing.T) {
+	client := newTestPipelineClient(ExportStatusDone)
+	export := NewExport(client)
+	export.statusPeriod = time.Millisecond
+
+	var statuses []string
+	cleaned := false
+
+	var b bytes.Buffer
+	status, err := export.RunToWriter(context.Background(), newTestPipelineJob(client), &b,
+		WithExportProgress(func(status *ExportStatus) {
+			statuses = append(statuses, status.Data.Status)
+		}),
+		WithExportCleanup(func() { cleaned = true }),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, ExportStatusDone, status.Data.Status)
+	assert.Equal(t, []string{ExportStatusInProgress, ExportStatusDone}, statuses)
+	assert.Equal(t, "user_id,3\n1,a@example.com\n2,b@example.com\n", b.String())
+	assert.False(t, cleaned)
+}
+
+func TestExport_RunRows(t *testing.T) {
+	client := newTestPipelineClient(ExportStatusDone)
+	export := NewExport(client)
+	export.statusPeriod = time.Millisecond
+
+	var emails []string
+	_, err := export.RunRows(context.Background(), newTestPipelineJob(client), func(header, row []string) error {
+		assert.Equal(t, []string{"user_id", "3"}, header)
+		emails = append(emails, row[1])
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, emails)
+}
+
+func TestExport_RunRowsCallbackError(t *testing.T) {
+	client := newTestPipelineClient(ExportStatusDone)
+	export := NewExport(client)
+	export.statusPeriod = time.Millisecond
+
+	stop := errors.New("stop")
+	cleaned := false
+
+	_, err := export.RunRows(context.Background(), newTestPipelineJob(client), func(header, row []string) error {
+		return stop
+	}, WithExportCleanup(func() { cleaned = true }))
+	assert.True(t, errors.Is(err, stop))
+	assert.True(t, cleaned)
+}
+
+func TestExport_RunJobError(t *testing.T) {
+	client := newTestPipelineClient(ExportStatusError)
+	export := NewExport(client)
+	export.statusPeriod = time.Millisecond
+
+	cleaned := false
+
+	var b bytes.Buffer
+	_, err := export.RunToWriter(context.Background(), newTestPipelineJob(client), &b,
+		WithExportCleanup(func() { cleaned = true }))
+	require.Error(t, err)
+
+	var jobErr *ExportJobError
+	require.True(t, errors.As(err, &jobErr))
+	assert.Equal(t, 7, jobErr.JobID)
+	assert.True(t, cleaned)
+	assert.Empty(t, b.String())
+	client.(*ClientMock).AssertNotCalled(t, "SendIO", mock.Anything)
+}
+
+func TestExport_RunCancelled(t *testing.T) {
+	client := newTestPipelineClient(ExportStatusDone)
+	export := NewExport(client)
+	export.statusPeriod = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cleaned := false
+
+	var b bytes.Buffer
+	_, err := export.RunToWriter(ctx, newTestPipelineJob(client), &b,
+		WithExportProgress(func(status *ExportStatus) {}),
+		WithExportCleanup(func() { cleaned = true }),
+		func(o *exportPipelineOptions) { cancel() },
+	)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, cleaned)
+}
+
+func TestExport_RunStartReplyCode(t *testing.T) {
+	client := NewClientMock()
+	export := NewExport(client)
+
+	job := ExportJob{
+		Start: func(ctx context.Context) (*ExportResult, error) {
+			result := &ExportResult{ReplyCode: ErrorCodeInvalidContactList, ReplyText: "Invalid contact list"}
+			return result, nil
+		},
+	}
+
+	var b bytes.Buffer
+	_, err := export.RunToWriter(context.Background(), job, &b)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidContactList))
+
+	var apiError *APIError
+	require.True(t, errors.As(err, &apiError))
+	assert.Equal(t, "Invalid contact list", apiError.ReplyText)
+
+	job.Start = func(ctx context.Context) (*ExportResult, error) {
+		return &ExportResult{ReplyText: "OK"}, nil
+	}
+
+	_, err = export.RunToWriter(context.Background(), job, &b)
+	require.Error(t, err)
+
+	client.(*ClientMock).AssertNotCalled(t, "Send", mock.Anything)
+	client.(*ClientMock).AssertNotCalled(t, "SendIO", mock.Anything)
+}
@@ -0,0 +1,131 @@
+package gomarsys
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// ExportRows streams the rows of an export:
+//
+//	rows, err := export.RowsContext(ctx, id, request.BaseExportRequest)
+//	if err != nil {
+//	}
+//	defer rows.Close()
+//	for rows.Next() {
+//		row := rows.Row()
+//	}
+//	if err := rows.Err(); err != nil {
+//	}
+type ExportRows struct {
+	stream io.ReadCloser
+	reader *csv.Reader
+	header []string
+	index  map[string]int
+	row    []string
+	err    error
+}
+
+func (e *Export) Rows(id int, request BaseExportRequest) (*ExportRows, error) {
+	return e.RowsContext(context.Background(), id, request)
+}
+
+// RowsContext starts downloading the export and returns a row iterator. The
+// request the export was started with tells which delimiter was used and
+// whether the first row is a header.
+func (e *Export) RowsContext(ctx context.Context, id int, request BaseExportRequest) (*ExportRows, error) {
+	delimiter := ','
+	if request.Delimiter != "" {
+		r, size := utf8.DecodeRuneInString(request.Delimiter)
+		if size != len(request.Delimiter) {
+			return nil, fmt.Errorf("unsupported delimiter: '%s'", request.Delimiter)
+		}
+		delimiter = r
+	}
+
+	r := &Request{
+		Path:   fmt.Sprintf("/v2/export/%d/data", id),
+		Method: requestGet,
+	}
+
+	stream, err := e.client.SendIOContext(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := &ExportRows{
+		stream: stream,
+		reader: csv.NewReader(stream),
+	}
+	rows.reader.Comma = delimiter
+	rows.reader.FieldsPerRecord = -1
+	rows.reader.ReuseRecord = true
+
+	if request.AddFieldNamesHeader == 1 {
+		header, err := rows.reader.Read()
+		if err != nil && err != io.EOF {
+			_ = stream.Close()
+			return nil, err
+		}
+
+		rows.header = append([]string(nil), header...)
+		rows.index = make(map[string]int, len(header))
+		for i, name := range rows.header {
+			rows.index[name] = i
+		}
+	}
+
+	return rows, nil
+}
+
+// Next reads the next row. It returns false at the end of the export or on
+// error, see Err.
+func (r *ExportRows) Next() bool {
+	if r.err != nil {
+		return false
+	}
+
+	row, err := r.reader.Read()
+	if err != nil {
+		if err != io.EOF {
+			r.err = err
+		}
+		r.row = nil
+		return false
+	}
+
+	r.row = row
+
+	return true
+}
+
+// Row returns the current row. The slice is reused by the following call to
+// Next, copy it to keep it.
+func (r *ExportRows) Row() []string {
+	return r.row
+}
+
+// Header returns the column names, or nil if the export has no header row.
+func (r *ExportRows) Header() []string {
+	return r.header
+}
+
+// Value returns the value of the named column in the current row.
+func (r *ExportRows) Value(column string) (string, bool) {
+	i, ok := r.index[column]
+	if !ok || i >= len(r.row) {
+		return "", false
+	}
+
+	return r.row[i], true
+}
+
+func (r *ExportRows) Err() error {
+	return r.err
+}
+
+func (r *ExportRows) Close() error {
+	return r.stream.Close()
+}
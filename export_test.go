@@ -77,3 +77,49 @@ func TestExport_DownloadExportToIO(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "123,10-1000,True", b.String())
 }
+
+func TestExport_Rows(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("SendIO", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/export/1/data")
+		assert.Equal(t, req.Method, RequestMethod(requestGet))
+	}).Return(NewReadCloser([]byte("user_id;Email;First Name\n123;a@test.ru;A\n124;b@test.ru;\"B;C\"\n")), nil)
+
+	export := NewExport(client)
+	rows, err := export.Rows(1, BaseExportRequest{Delimiter: ";", AddFieldNamesHeader: 1})
+	require.NoError(t, err)
+	defer func() { _ = rows.Close() }()
+
+	assert.Equal(t, []string{"user_id", "Email", "First Name"}, rows.Header())
+
+	var data [][]string
+	for rows.Next() {
+		data = append(data, append([]string(nil), rows.Row()...))
+
+		_, ok := rows.Value("Email")
+		assert.True(t, ok)
+	}
+	require.NoError(t, rows.Err())
+
+	assert.Equal(t, [][]string{
+		{"123", "a@test.ru", "A"},
+		{"124", "b@test.ru", "B;C"},
+	}, data)
+}
+
+func TestExport_RowsWithoutHeader(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("SendIO", mock.Anything).Return(NewReadCloser([]byte(`123,10-1000,True`)), nil)
+
+	export := NewExport(client)
+	rows, err := export.Rows(1, BaseExportRequest{})
+	require.NoError(t, err)
+
+	require.True(t, rows.Next())
+	assert.Nil(t, rows.Header())
+	assert.Equal(t, []string{"123", "10-1000", "True"}, rows.Row())
+	assert.False(t, rows.Next())
+	require.NoError(t, rows.Err())
+}
@@ -0,0 +1,130 @@
+package gomarsys
+
+import (
+	"fmt"
+	"time"
+)
+
+// minWaitInterval keeps the status checks from running in a tight loop.
+const minWaitInterval = time.Millisecond
+
+type WaitOptions func(o *waitOptions)
+
+type waitOptions struct {
+	initialDelay time.Duration
+	interval     time.Duration
+	factor       float64
+	maxInterval  time.Duration
+	maxWait      time.Duration
+	onStatus     func(status *ExportStatus)
+	onChange     func(previous, current *ExportStatus)
+}
+
+// ExportJobError is returned when an export job ends in the error state.
+type ExportJobError struct {
+	JobID  int
+	Status *ExportStatus
+}
+
+func (e *ExportJobError) Error() string {
+	return fmt.Sprintf("export failed, job id: %d", e.JobID)
+}
+
+// ExportWaitError is returned when the job is not complete within the maximum
+// wait. Status is the last status received, if any.
+type ExportWaitError struct {
+	JobID  int
+	Status *ExportStatus
+	err    error
+}
+
+func (e *ExportWaitError) Error() string {
+	return fmt.Sprintf("export not complete, job id: %d: %s", e.JobID, e.err)
+}
+
+func (e *ExportWaitError) Unwrap() error {
+	return e.err
+}
+
+// WithWaitInitialDelay sets the delay before the first status check. Zero
+// checks immediately.
+func WithWaitInitialDelay(d time.Duration) WaitOptions {
+	return func(o *waitOptions) {
+		if d >= 0 {
+			o.initialDelay = d
+		}
+	}
+}
+
+// WithWaitInterval sets the delay between status checks. Values of zero or
+// less are ignored.
+func WithWaitInterval(d time.Duration) WaitOptions {
+	return func(o *waitOptions) {
+		if d > 0 {
+			o.interval = d
+		}
+	}
+}
+
+// WithWaitBackoff multiplies the interval by factor after every check.
+func WithWaitBackoff(factor float64) WaitOptions {
+	return func(o *waitOptions) {
+		o.factor = factor
+	}
+}
+
+// WithWaitMaxInterval caps the interval grown by WithWaitBackoff.
+func WithWaitMaxInterval(d time.Duration) WaitOptions {
+	return func(o *waitOptions) {
+		o.maxInterval = d
+	}
+}
+
+// WithWaitTimeout limits the total time spent waiting for the job.
+func WithWaitTimeout(d time.Duration) WaitOptions {
+	return func(o *waitOptions) {
+		o.maxWait = d
+	}
+}
+
+// WithWaitStatusChange calls f whenever the job status changes, including the
+// first status received, when previous is nil.
+func WithWaitStatusChange(f func(previous, current *ExportStatus)) WaitOptions {
+	return func(o *waitOptions) {
+		o.onChange = f
+	}
+}
+
+func (e *Export) newWaitOptions(opts []WaitOptions) *waitOptions {
+	return newWaitOptions(e.statusPeriod, e.statusPeriod, opts)
+}
+
+func newWaitOptions(initialDelay, interval time.Duration, opts []WaitOptions) *waitOptions {
+	o := &waitOptions{
+		initialDelay: initialDelay,
+		interval:     interval,
+		factor:       1,
+	}
+
+	for _, f := range opts {
+		f(o)
+	}
+
+	return o
+}
+
+func (o *waitOptions) next(interval time.Duration) time.Duration {
+	if o.factor > 1 {
+		interval = time.Duration(float64(interval) * o.factor)
+	}
+
+	if o.maxInterval > 0 && interval > o.maxInterval {
+		interval = o.maxInterval
+	}
+
+	if interval < minWaitInterval {
+		interval = minWaitInterval
+	}
+
+	return interval
+}
@@ -0,0 +1,149 @@
+package gomarsys
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWaitClient(statuses ...string) ClientInterface {
+	client := NewClientMock()
+	for i, status := range statuses {
+		call := client.(*ClientMock).On("Send", mock.Anything).
+			Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"id":"7","status":"`+status+`"}}`), nil)
+		if i < len(statuses)-1 {
+			call.Once()
+		}
+	}
+
+	return client
+}
+
+func TestExport_WaitExportComplete(t *testing.T) {
+	client := newTestWaitClient(ExportStatusScheduled, ExportStatusInProgress, ExportStatusInProgress, ExportStatusDone)
+	export := NewExport(client)
+
+	var changes [][2]string
+
+	status, err := export.WaitExportComplete(context.Background(), 7,
+		WithWaitInitialDelay(0),
+		WithWaitInterval(time.Millisecond),
+		WithWaitBackoff(2),
+		WithWaitMaxInterval(3*time.Millisecond),
+		WithWaitStatusChange(func(previous, current *ExportStatus) {
+			from := ""
+			if previous != nil {
+				from = previous.Data.Status
+			}
+			changes = append(changes, [2]string{from, current.Data.Status})
+		}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, ExportStatusDone, status.Data.Status)
+	assert.Equal(t, [][2]string{
+		{"", ExportStatusScheduled},
+		{ExportStatusScheduled, ExportStatusInProgress},
+		{ExportStatusInProgress, ExportStatusDone},
+	}, changes)
+	client.(*ClientMock).AssertNumberOfCalls(t, "Send", 4)
+}
+
+func TestExport_WaitExportCompleteJobError(t *testing.T) {
+	client := newTestWaitClient(ExportStatusInProgress, ExportStatusError)
+	export := NewExport(client)
+
+	status, err := export.WaitExportComplete(context.Background(), 7,
+		WithWaitInitialDelay(0), WithWaitInterval(time.Millisecond))
+	require.Error(t, err)
+	assert.Nil(t, status)
+
+	var jobErr *ExportJobError
+	require.True(t, errors.As(err, &jobErr))
+	assert.Equal(t, 7, jobErr.JobID)
+	assert.Equal(t, ExportStatusError, jobErr.Status.Data.Status)
+}
+
+func TestExport_WaitExportCompleteTimeout(t *testing.T) {
+	client := newTestWaitClient(ExportStatusInProgress)
+	export := NewExport(client)
+
+	_, err := export.WaitExportComplete(context.Background(), 7,
+		WithWaitInitialDelay(0),
+		WithWaitInterval(5*time.Millisecond),
+		WithWaitTimeout(20*time.Millisecond))
+	require.Error(t, err)
+
+	var waitErr *ExportWaitError
+	require.True(t, errors.As(err, &waitErr))
+	assert.Equal(t, ExportStatusInProgress, waitErr.Status.Data.Status)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestExport_WaitExportCompleteStatusTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		<-req.Context().Done()
+	}))
+	defer server.Close()
+
+	export := NewExport(NewClient("test", "test", WithCustomHost(server.URL+"/")))
+
+	_, err := export.WaitExportComplete(context.Background(), 7,
+		WithWaitInitialDelay(0),
+		WithWaitTimeout(20*time.Millisecond))
+	require.Error(t, err)
+
+	var waitErr *ExportWaitError
+	require.True(t, errors.As(err, &waitErr))
+	assert.Nil(t, waitErr.Status)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestExport_WaitExportCompleteCallerDeadline(t *testing.T) {
+	client := newTestWaitClient(ExportStatusInProgress)
+	export := NewExport(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := export.WaitExportComplete(ctx, 7,
+		WithWaitInitialDelay(0),
+		WithWaitInterval(5*time.Millisecond),
+		WithWaitTimeout(time.Hour))
+	require.Error(t, err)
+
+	var waitErr *ExportWaitError
+	assert.False(t, errors.As(err, &waitErr))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestWaitOptions_Next(t *testing.T) {
+	o := &waitOptions{factor: 1.5, maxInterval: 4 * time.Second}
+
+	assert.Equal(t, 3*time.Second, o.next(2*time.Second))
+	assert.Equal(t, 4*time.Second, o.next(3*time.Second))
+
+	o = &waitOptions{factor: 1}
+	assert.Equal(t, 2*time.Second, o.next(2*time.Second))
+}
+
+func TestWaitOptions_NonPositiveInterval(t *testing.T) {
+	export := NewExport(NewClientMock())
+
+	o := export.newWaitOptions([]WaitOptions{WithWaitInterval(0), WithWaitInitialDelay(-time.Second)})
+	assert.Equal(t, export.statusPeriod, o.interval)
+	assert.Equal(t, export.statusPeriod, o.initialDelay)
+
+	o = export.newWaitOptions([]WaitOptions{WithWaitInterval(-time.Second)})
+	assert.Equal(t, export.statusPeriod, o.interval)
+
+	o = &waitOptions{factor: 1}
+	assert.Equal(t, minWaitInterval, o.next(0))
+	assert.Equal(t, minWaitInterval, o.next(-time.Second))
+}
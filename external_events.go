@@ -1,6 +1,7 @@
 package gomarsys
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -31,6 +32,10 @@ func NewExternalEvents(client ClientInterface) *ExternalEvents {
 }
 
 func (e *ExternalEvents) TriggerEvent(eventId int, event interface{}) error {
+	return e.TriggerEventContext(context.Background(), eventId, event)
+}
+
+func (e *ExternalEvents) TriggerEventContext(ctx context.Context, eventId int, event interface{}) error {
 	data, err := json.Marshal(event)
 	if err != nil {
 		return err
@@ -42,9 +47,14 @@ func (e *ExternalEvents) TriggerEvent(eventId int, event interface{}) error {
 		Body:   data,
 	}
 
-	if _, err := e.client.Send(r); err != nil {
+	response, err := e.client.SendContext(ctx, r)
+	if err != nil {
 		return err
 	}
 
+	if apiError := checkReplyCode(response, r); apiError != nil {
+		return apiError
+	}
+
 	return nil
 }
@@ -0,0 +1,159 @@
+package gomarsys
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FieldRegistry resolves field names and string ids of an account to numeric
+// field ids and back. The field list is loaded on first use and kept until
+// Refresh is called.
+type FieldRegistry struct {
+	fields *Fields
+
+	mu       sync.RWMutex
+	loaded   bool
+	byID     map[int]Field
+	byString map[string]int
+	byName   map[string]int
+}
+
+func NewFieldRegistry(fields *Fields) *FieldRegistry {
+	return &FieldRegistry{
+		fields: fields,
+	}
+}
+
+// Load fetches the field list unless it is already loaded.
+func (r *FieldRegistry) Load(ctx context.Context) error {
+	r.mu.RLock()
+	loaded := r.loaded
+	r.mu.RUnlock()
+
+	if loaded {
+		return nil
+	}
+
+	return r.Refresh(ctx)
+}
+
+// Refresh fetches the field list again, e.g. after fields were created.
+func (r *FieldRegistry) Refresh(ctx context.Context) error {
+	list, err := r.fields.ListContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[int]Field, len(list))
+	byString := make(map[string]int, len(list))
+	byName := make(map[string]int, len(list))
+
+	for _, field := range list {
+		byID[field.ID] = field
+		if field.StringID != "" {
+			byString[field.StringID] = field.ID
+		}
+		byName[strings.ToLower(field.Name)] = field.ID
+	}
+
+	r.mu.Lock()
+	r.byID, r.byString, r.byName = byID, byString, byName
+	r.loaded = true
+	r.mu.Unlock()
+
+	return nil
+}
+
+// ID resolves a numeric id, string id or case-insensitive field name to the
+// numeric field id.
+func (r *FieldRegistry) ID(ctx context.Context, name string) (int, error) {
+	if err := r.Load(ctx); err != nil {
+		return 0, err
+	}
+
+	if id, ok := r.lookup(name); ok {
+		return id, nil
+	}
+
+	return 0, fmt.Errorf("unknown field '%s'", name)
+}
+
+// IDs resolves several names at once, see ID.
+func (r *FieldRegistry) IDs(ctx context.Context, names []string) ([]int, error) {
+	ids := make([]int, len(names))
+
+	for i, name := range names {
+		id, err := r.ID(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+
+	return ids, nil
+}
+
+// Field returns the definition of a field by its numeric id.
+func (r *FieldRegistry) Field(ctx context.Context, id int) (Field, error) {
+	if err := r.Load(ctx); err != nil {
+		return Field{}, err
+	}
+
+	r.mu.RLock()
+	field, ok := r.byID[id]
+	r.mu.RUnlock()
+
+	if !ok {
+		return Field{}, fmt.Errorf("unknown field id %d", id)
+	}
+
+	return field, nil
+}
+
+// All returns all loaded fields.
+func (r *FieldRegistry) All(ctx context.Context) ([]Field, error) {
+	if err := r.Load(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fields := make([]Field, 0, len(r.byID))
+	for _, field := range r.byID {
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+func (r *FieldRegistry) lookup(name string) (int, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if id, err := strconv.Atoi(name); err == nil {
+		_, ok := r.byID[id]
+		return id, ok
+	}
+
+	if id, ok := r.byString[name]; ok {
+		return id, true
+	}
+
+	id, ok := r.byName[strings.ToLower(name)]
+
+	return id, ok
+}
+
+// resolve is a fieldResolver over the loaded fields falling back to the
+// system field names.
+func (r *FieldRegistry) resolve(name string) (int, bool) {
+	if id, ok := r.lookup(name); ok {
+		return id, true
+	}
+
+	return resolveSystemField(name)
+}
@@ -0,0 +1,80 @@
+package gomarsys
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testFieldListResponse = `{"replyCode":0,"replyText":"OK","data":[` +
+	`{"id":3,"name":"Email","application_type":"email","string_id":"email"},` +
+	`{"id":5,"name":"Gender","application_type":"singlechoice","string_id":"gender"},` +
+	`{"id":1234,"name":"Loyalty level","application_type":"singlechoice","string_id":"loyalty_level"},` +
+	`{"id":1235,"name":"Favourite brands","application_type":"multichoice","string_id":"favourite_brands"}]}`
+
+func newTestFieldsClient() *ClientMock {
+	client := NewClientMock().(*ClientMock)
+	client.On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/field"
+	})).Return([]byte(testFieldListResponse), nil)
+
+	return client
+}
+
+func TestFieldRegistry_ID(t *testing.T) {
+	client := newTestFieldsClient()
+	registry := NewFieldRegistry(NewFields(client))
+
+	ctx := context.Background()
+
+	id, err := registry.ID(ctx, "loyalty_level")
+	require.NoError(t, err)
+	assert.Equal(t, 1234, id)
+
+	id, err = registry.ID(ctx, "loyalty LEVEL")
+	require.NoError(t, err)
+	assert.Equal(t, 1234, id)
+
+	id, err = registry.ID(ctx, "1234")
+	require.NoError(t, err)
+	assert.Equal(t, 1234, id)
+
+	_, err = registry.ID(ctx, "unknown")
+	require.Error(t, err)
+
+	field, err := registry.Field(ctx, 1234)
+	require.NoError(t, err)
+	assert.Equal(t, "Loyalty level", field.Name)
+
+	client.AssertNumberOfCalls(t, "Send", 1)
+
+	require.NoError(t, registry.Refresh(ctx))
+	client.AssertNumberOfCalls(t, "Send", 2)
+}
+
+func TestUsers_GetUserInfoByNames(t *testing.T) {
+	client := newTestFieldsClient()
+	client.On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/contact/getdata"
+	})).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		var v struct {
+			KeyID  string   `json:"keyId"`
+			Fields []string `json:"fields"`
+		}
+		require.NoError(t, json.Unmarshal(req.Body, &v))
+		assert.Equal(t, "3", v.KeyID)
+		assert.Equal(t, []string{"1234", "1"}, v.Fields)
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"errors":[],"result":[{"1234":"2","1":"Test","id":"111111"}]}}`), nil)
+
+	user := NewUsers(client, WithFieldRegistry(NewFieldRegistry(NewFields(client))))
+	userData, err := user.GetUserInfoByNames("email", "test@test.ru", []string{"Loyalty level", "first_name"})
+	require.NoError(t, err)
+	assert.Equal(t, "2", userData.Data[1234])
+	assert.Equal(t, "Test", userData.Data[FirstName])
+}
@@ -0,0 +1,168 @@
+package gomarsys
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	FieldTypeShortText    = "shorttext"
+	FieldTypeLongText     = "longtext"
+	FieldTypeLargeText    = "largetext"
+	FieldTypeDate         = "date"
+	FieldTypeBirthDate    = "birthdate"
+	FieldTypeURL          = "url"
+	FieldTypeNumeric      = "numeric"
+	FieldTypeSingleChoice = "singlechoice"
+	FieldTypeMultiChoice  = "multichoice"
+)
+
+type Fields struct {
+	client ClientInterface
+}
+
+type Field struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	ApplicationType string `json:"application_type"`
+	StringID        string `json:"string_id"`
+}
+
+type FieldChoice struct {
+	ID          string
+	Choice      string
+	BitPosition int
+}
+
+func NewFields(client ClientInterface) *Fields {
+	return &Fields{
+		client: client,
+	}
+}
+
+func (f *Fields) List() ([]Field, error) {
+	return f.ListContext(context.Background())
+}
+
+// ListContext returns all contact fields defined on the account, system and
+// custom ones.
+func (f *Fields) ListContext(ctx context.Context) ([]Field, error) {
+	r := &Request{
+		Path:   "/v2/field",
+		Method: requestGet,
+	}
+
+	var res struct {
+		ReplyCode int     `json:"replyCode"`
+		ReplyText string  `json:"replyText"`
+		Data      []Field `json:"data"`
+	}
+
+	if response, err := f.client.SendContext(ctx, r); err != nil {
+		return nil, err
+	} else {
+		if apiError := checkReplyCode(response, r); apiError != nil {
+			return nil, apiError
+		}
+		if err := json.Unmarshal(response, &res); err != nil {
+			return nil, err
+		}
+	}
+
+	return res.Data, nil
+}
+
+func (f *Fields) Create(name, applicationType, stringID string) (int, error) {
+	return f.CreateContext(context.Background(), name, applicationType, stringID)
+}
+
+// CreateContext creates a custom field and returns its id. stringID is
+// optional.
+func (f *Fields) CreateContext(ctx context.Context, name, applicationType, stringID string) (int, error) {
+	type request struct {
+		Name            string `json:"name"`
+		ApplicationType string `json:"application_type"`
+		StringID        string `json:"string_id,omitempty"`
+	}
+
+	data, err := json.Marshal(&request{
+		Name:            name,
+		ApplicationType: applicationType,
+		StringID:        stringID,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	r := &Request{
+		Path:   "/v2/field",
+		Method: requestPost,
+		Body:   data,
+	}
+
+	var res struct {
+		ReplyCode int    `json:"replyCode"`
+		ReplyText string `json:"replyText"`
+		Data      struct {
+			ID int `json:"id"`
+		} `json:"data"`
+	}
+
+	if response, err := f.client.SendContext(ctx, r); err != nil {
+		return 0, err
+	} else {
+		if apiError := checkReplyCode(response, r); apiError != nil {
+			return 0, apiError
+		}
+		if err := json.Unmarshal(response, &res); err != nil {
+			return 0, err
+		}
+	}
+
+	return res.Data.ID, nil
+}
+
+func (f *Fields) Choices(fieldID int) ([]FieldChoice, error) {
+	return f.ChoicesContext(context.Background(), fieldID)
+}
+
+// ChoicesContext returns the choices of a single-choice or multi-choice field.
+func (f *Fields) ChoicesContext(ctx context.Context, fieldID int) ([]FieldChoice, error) {
+	r := &Request{
+		Path:   fmt.Sprintf("/v2/field/%d/choice", fieldID),
+		Method: requestGet,
+	}
+
+	var res struct {
+		ReplyCode int    `json:"replyCode"`
+		ReplyText string `json:"replyText"`
+		Data      []struct {
+			ID          flexibleID `json:"id"`
+			Choice      string     `json:"choice"`
+			BitPosition int        `json:"bit_position"`
+		} `json:"data"`
+	}
+
+	if response, err := f.client.SendContext(ctx, r); err != nil {
+		return nil, err
+	} else {
+		if apiError := checkReplyCode(response, r); apiError != nil {
+			return nil, apiError
+		}
+		if err := json.Unmarshal(response, &res); err != nil {
+			return nil, err
+		}
+	}
+
+	choices := make([]FieldChoice, len(res.Data))
+	for i, item := range res.Data {
+		choices[i] = FieldChoice{
+			ID:          string(item.ID),
+			Choice:      item.Choice,
+			BitPosition: item.BitPosition,
+		}
+	}
+
+	return choices, nil
+}
@@ -0,0 +1,68 @@
+package gomarsys
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFields_List(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/field")
+		assert.Equal(t, req.Method, RequestMethod(requestGet))
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":[{"id":3,"name":"Email","application_type":"email","string_id":"email"},{"id":1234,"name":"Loyalty level","application_type":"singlechoice","string_id":"loyalty_level"}]}`), nil)
+
+	fields := NewFields(client)
+	list, err := fields.List()
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, Field{ID: 1234, Name: "Loyalty level", ApplicationType: FieldTypeSingleChoice, StringID: "loyalty_level"}, list[1])
+}
+
+func TestFields_Create(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/field")
+		assert.Equal(t, req.Method, RequestMethod(requestPost))
+
+		var v map[string]string
+		require.NoError(t, json.Unmarshal(req.Body, &v))
+		assert.Equal(t, map[string]string{
+			"name":             "Loyalty level",
+			"application_type": FieldTypeSingleChoice,
+			"string_id":        "loyalty_level",
+		}, v)
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"id":1234}}`), nil)
+
+	fields := NewFields(client)
+	id, err := fields.Create("Loyalty level", FieldTypeSingleChoice, "loyalty_level")
+	require.NoError(t, err)
+	assert.Equal(t, 1234, id)
+}
+
+func TestFields_Choices(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/field/5/choice")
+		assert.Equal(t, req.Method, RequestMethod(requestGet))
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":[{"id":"1","choice":"Male","bit_position":1},{"id":2,"choice":"Female","bit_position":2}]}`), nil)
+
+	fields := NewFields(client)
+	choices, err := fields.Choices(Gender)
+	require.NoError(t, err)
+	require.Len(t, choices, 2)
+	assert.Equal(t, "1", choices[0].ID)
+	assert.Equal(t, "Male", choices[0].Choice)
+	assert.Equal(t, "2", choices[1].ID)
+	assert.Equal(t, "Female", choices[1].Choice)
+}
@@ -0,0 +1,380 @@
+package gomarsys
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tagName = "emarsys"
+
+	tagOptionOmitEmpty = "omitempty"
+	tagOptionLabel     = "label"
+
+	// Emarsys yes/no choice fields, e.g. OptIn, use 1 for true and 2 for false
+	choiceTrue  = "1"
+	choiceFalse = "2"
+)
+
+var systemFieldNames = map[string]int{
+	"interests":      Interests,
+	"first_name":     FirstName,
+	"last_name":      LastName,
+	"email":          EMail,
+	"birth_date":     DateOfBirth,
+	"gender":         Gender,
+	"marital_status": MaritalStatus,
+	"children":       Children,
+	"education":      Education,
+	"title":          Title,
+	"address":        Address,
+	"city":           City,
+	"state":          State,
+	"zip":            ZIPCode,
+	"country":        Country,
+	"phone":          Phone,
+	"optin":          OptIn,
+}
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	stringSliceType = reflect.TypeOf([]string(nil))
+)
+
+// fieldResolver maps a field name used in struct tags to its numeric id.
+type fieldResolver func(name string) (int, bool)
+
+func resolveSystemField(name string) (int, bool) {
+	id, ok := systemFieldNames[name]
+	return id, ok
+}
+
+// fieldCodec holds what marshalling needs beyond reflection: name resolution
+// and, for fields tagged with ",label", translation of choice ids.
+type fieldCodec struct {
+	resolve fieldResolver
+	labels  func(fieldID int, value string) ([]string, error)
+	value   func(fieldID int, labels []string) (string, error)
+}
+
+var systemFieldCodec = &fieldCodec{resolve: resolveSystemField}
+
+type taggedField struct {
+	index     int
+	id        int
+	omitEmpty bool
+	label     bool
+}
+
+// Marshal converts a struct with `emarsys` tags into contact data. A tag holds
+// either a numeric field id (`emarsys:"3"`) or a system field name
+// (`emarsys:"email"`), optionally followed by ",omitempty". A nil pointer is
+// written as an empty value, clearing the field, unless the tag has
+// ",omitempty". Booleans are written as Emarsys yes/no choices and
+// time.Time values as dates. The ",label" option, which maps choice fields to
+// string or []string labels, is only supported through Users configured with
+// a ChoiceTranslator.
+func Marshal(v interface{}) (map[int]string, error) {
+	return marshal(v, systemFieldCodec)
+}
+
+// Unmarshal fills a struct with `emarsys` tags from contact data, see Marshal.
+// Pointer fields are set to nil for empty values and for fields missing from
+// data, which is how null values are dropped; other fields missing from data
+// are left untouched.
+func Unmarshal(data map[int]string, v interface{}) error {
+	return unmarshal(data, v, systemFieldCodec)
+}
+
+func marshal(v interface{}, c *fieldCodec) (map[int]string, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot marshal %T: struct expected", v)
+	}
+
+	fields, err := taggedFields(rv.Type(), c.resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(map[int]string, len(fields))
+
+	for _, f := range fields {
+		fv := rv.Field(f.index)
+
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				if !f.omitEmpty {
+					data[f.id] = ""
+				}
+				continue
+			}
+			fv = fv.Elem()
+		}
+
+		if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+
+		var s string
+		if f.label {
+			s, err = c.formatLabels(f.id, fv)
+		} else {
+			s, err = formatValue(fv)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", rv.Type().Field(f.index).Name, err)
+		}
+
+		data[f.id] = s
+	}
+
+	return data, nil
+}
+
+func unmarshal(data map[int]string, v interface{}, c *fieldCodec) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot unmarshal into %T: pointer to struct expected", v)
+	}
+
+	rv = rv.Elem()
+
+	fields, err := taggedFields(rv.Type(), c.resolve)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range fields {
+		fv := rv.Field(f.index)
+		value, ok := data[f.id]
+
+		if fv.Kind() == reflect.Ptr {
+			if !ok || value == "" {
+				fv.Set(reflect.Zero(fv.Type()))
+				continue
+			}
+
+			fv.Set(reflect.New(fv.Type().Elem()))
+			fv = fv.Elem()
+		} else if !ok {
+			continue
+		}
+
+		if f.label {
+			err = c.parseLabels(f.id, value, fv)
+		} else {
+			err = parseValue(value, fv)
+		}
+		if err != nil {
+			return fmt.Errorf("field %s: %w", rv.Type().Field(f.index).Name, err)
+		}
+	}
+
+	return nil
+}
+
+// fieldIDs returns the ids of all tagged fields of the struct v points to.
+func fieldIDs(v interface{}, resolve fieldResolver) ([]int, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot get fields of %T: struct expected", v)
+	}
+
+	fields, err := taggedFields(t, resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(fields))
+	for i, f := range fields {
+		ids[i] = f.id
+	}
+
+	return ids, nil
+}
+
+func taggedFields(t reflect.Type, resolve fieldResolver) ([]taggedField, error) {
+	var fields []taggedField
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		tag, ok := sf.Tag.Lookup(tagName)
+		if !ok || tag == "-" || sf.PkgPath != "" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		f := taggedField{index: i}
+
+		for _, option := range parts[1:] {
+			switch option {
+			case tagOptionOmitEmpty:
+				f.omitEmpty = true
+			case tagOptionLabel:
+				f.label = true
+			}
+		}
+
+		if id, err := strconv.Atoi(parts[0]); err == nil {
+			f.id = id
+		} else if id, ok := resolve(parts[0]); ok {
+			f.id = id
+		} else {
+			return nil, fmt.Errorf("field %s: unknown emarsys field '%s'", sf.Name, parts[0])
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
+
+func (c *fieldCodec) formatLabels(fieldID int, v reflect.Value) (string, error) {
+	if c.value == nil {
+		return "", fmt.Errorf("label option requires a choice translator")
+	}
+
+	var labels []string
+
+	switch {
+	case v.Kind() == reflect.String:
+		if v.String() != "" {
+			labels = []string{v.String()}
+		}
+	case v.Type().ConvertibleTo(stringSliceType):
+		labels = v.Convert(stringSliceType).Interface().([]string)
+	default:
+		return "", fmt.Errorf("unsupported type %s for label", v.Type())
+	}
+
+	return c.value(fieldID, labels)
+}
+
+func (c *fieldCodec) parseLabels(fieldID int, s string, v reflect.Value) error {
+	if c.labels == nil {
+		return fmt.Errorf("label option requires a choice translator")
+	}
+
+	labels, err := c.labels(fieldID, s)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case v.Kind() == reflect.String:
+		if len(labels) > 0 {
+			v.SetString(labels[0])
+		} else {
+			v.SetString("")
+		}
+	case v.Type().ConvertibleTo(stringSliceType):
+		v.Set(reflect.ValueOf(labels).Convert(v.Type()))
+	default:
+		return fmt.Errorf("unsupported type %s for label", v.Type())
+	}
+
+	return nil
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).IsZero()
+	}
+
+	return v.IsZero()
+}
+
+func formatValue(v reflect.Value) (string, error) {
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return "", nil
+		}
+		return t.Format(mysqlDateFormat), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		if v.Bool() {
+			return choiceTrue, nil
+		}
+		return choiceFalse, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+func parseValue(s string, v reflect.Value) error {
+	if v.Type() == timeType {
+		if s == "" {
+			v.Set(reflect.Zero(timeType))
+			return nil
+		}
+
+		t, err := time.Parse(mysqlDateFormat, s)
+		if err != nil {
+			return err
+		}
+
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if s == "" && v.Kind() != reflect.String {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		switch strings.ToLower(s) {
+		case choiceTrue, "true":
+			v.SetBool(true)
+		case choiceFalse, "0", "false":
+			v.SetBool(false)
+		default:
+			return fmt.Errorf("cannot parse '%s' as bool", s)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
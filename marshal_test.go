@@ -0,0 +1,124 @@
+package gomarsys
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type testContact struct {
+	FirstName string    `emarsys:"first_name"`
+	Email     string    `emarsys:"3"`
+	Phone     string    `emarsys:"phone,omitempty"`
+	BirthDate time.Time `emarsys:"birth_date"`
+	OptIn     bool      `emarsys:"optin"`
+	Children  *int      `emarsys:"children"`
+	Custom    *string   `emarsys:"1234"`
+	Ignored   string    `emarsys:"-"`
+	Untagged  string
+}
+
+func TestMarshal(t *testing.T) {
+	data, err := Marshal(testContact{
+		FirstName: "Test",
+		Email:     "test@test.ru",
+		BirthDate: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+		OptIn:     true,
+		Ignored:   "ignored",
+		Untagged:  "untagged",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[int]string{
+		FirstName:   "Test",
+		EMail:       "test@test.ru",
+		DateOfBirth: "1990-05-17",
+		OptIn:       "1",
+		Children:    "",
+		1234:        "",
+	}, data)
+}
+
+func TestMarshal_NilPointerOmitEmpty(t *testing.T) {
+	data, err := Marshal(struct {
+		Email  string  `emarsys:"email"`
+		Custom *string `emarsys:"1234,omitempty"`
+	}{Email: "test@test.ru"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[int]string{EMail: "test@test.ru"}, data)
+}
+
+func TestUnmarshal_ResetsPointers(t *testing.T) {
+	custom := "stale"
+	children := 3
+	contact := testContact{Custom: &custom, Children: &children}
+
+	err := Unmarshal(map[int]string{
+		EMail:    "test@test.ru",
+		Children: "",
+	}, &contact)
+	require.NoError(t, err)
+
+	assert.Equal(t, "test@test.ru", contact.Email)
+	assert.Nil(t, contact.Children)
+	assert.Nil(t, contact.Custom)
+}
+
+func TestUnmarshal(t *testing.T) {
+	var contact testContact
+
+	err := Unmarshal(map[int]string{
+		FirstName:   "Test",
+		EMail:       "test@test.ru",
+		DateOfBirth: "1990-05-17",
+		OptIn:       "2",
+		Children:    "2",
+	}, &contact)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Test", contact.FirstName)
+	assert.Equal(t, "test@test.ru", contact.Email)
+	assert.Equal(t, time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC), contact.BirthDate)
+	assert.False(t, contact.OptIn)
+	require.NotNil(t, contact.Children)
+	assert.Equal(t, 2, *contact.Children)
+	assert.Nil(t, contact.Custom)
+}
+
+func TestMarshal_UnknownField(t *testing.T) {
+	_, err := Marshal(struct {
+		Name string `emarsys:"unknown"`
+	}{})
+	require.Error(t, err)
+}
+
+func TestUsers_GetUserInfoInto(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		var v struct {
+			Fields []string `json:"fields"`
+		}
+		require.NoError(t, json.Unmarshal(req.Body, &v))
+		assert.Equal(t, []string{"1", "3", "15", "4", "31", "7", "1234"}, v.Fields)
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"errors":[],"result":[{"1":"Test","3":"test@test.ru","4":"1990-05-17","31":"1","7":null,"1234":"custom","id":"111111","uid":"fd90tidfpd"}]}}`), nil)
+
+	var contact testContact
+
+	user := NewUsers(client)
+	id, err := user.GetUserInfoInto(EMail, "test@test.ru", &contact)
+	require.NoError(t, err)
+
+	assert.Equal(t, "111111", id)
+	assert.Equal(t, "Test", contact.FirstName)
+	assert.True(t, contact.OptIn)
+	assert.Nil(t, contact.Children)
+	require.NotNil(t, contact.Custom)
+	assert.Equal(t, "custom", *contact.Custom)
+}
@@ -0,0 +1,156 @@
+package gomarsys
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	headerRateLimitRemaining = "X-Ratelimit-Remaining"
+	headerRateLimitReset     = "X-Ratelimit-Reset"
+
+	// values of X-Ratelimit-Reset above this are unix timestamps, below are
+	// seconds until the quota resets
+	rateLimitResetTimestampThreshold = 1000000000
+)
+
+// rateLimiter is a token bucket which additionally blocks until the quota
+// window resets once the server reports no remaining requests.
+type rateLimiter struct {
+	mu           sync.Mutex
+	rate         float64
+	burst        float64
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+// WithRateLimit limits the client to requestsPerSecond with bursts of up to
+// burst requests. The limiter is shared by every service using the client and
+// follows the X-Ratelimit-* headers returned by Emarsys. A requestsPerSecond
+// of zero or less disables the client-side limit, so only the headers are
+// followed.
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOptions {
+	return func(options *options) {
+		options.rateLimiter = newRateLimiter(requestsPerSecond, burst)
+	}
+}
+
+func newRateLimiter(requestsPerSecond float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		delay := l.reserve(time.Now())
+		if delay <= 0 {
+			return nil
+		}
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a token and returns zero, or returns how long to wait before
+// trying again.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+
+	if now.Before(l.blockedUntil) {
+		return l.blockedUntil.Sub(now)
+	}
+
+	// headers-only mode
+	if l.rate <= 0 {
+		return 0
+	}
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+func (l *rateLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens += elapsed.Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+	}
+}
+
+func (l *rateLimiter) update(resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get(headerRateLimitRemaining))
+	if err != nil {
+		if resp.StatusCode != http.StatusTooManyRequests {
+			return
+		}
+		remaining = 0
+	}
+
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+
+	if float64(remaining) < l.tokens {
+		l.tokens = float64(remaining)
+	}
+
+	if remaining > 0 {
+		return
+	}
+
+	reset, ok := parseRateLimitReset(resp.Header.Get(headerRateLimitReset), now)
+	if !ok {
+		reset, ok = parseRetryAfter(resp.Header.Get("Retry-After"))
+	}
+	if !ok {
+		return
+	}
+
+	if until := now.Add(reset); until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+}
+
+func parseRateLimitReset(value string, now time.Time) (time.Duration, bool) {
+	reset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || reset < 0 {
+		return 0, false
+	}
+
+	if reset < rateLimitResetTimestampThreshold {
+		return time.Duration(reset) * time.Second, true
+	}
+
+	d := time.Unix(reset, 0).Sub(now)
+	if d < 0 {
+		d = 0
+	}
+
+	return d, true
+}
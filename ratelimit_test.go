@@ -0,0 +1,82 @@
+package gomarsys
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	now := time.Now()
+	limiter := newRateLimiter(10, 2)
+	limiter.last = now
+
+	assert.Equal(t, time.Duration(0), limiter.reserve(now))
+	assert.Equal(t, time.Duration(0), limiter.reserve(now))
+	assert.Equal(t, 100*time.Millisecond, limiter.reserve(now))
+	assert.Equal(t, time.Duration(0), limiter.reserve(now.Add(100*time.Millisecond)))
+}
+
+func TestRateLimiter_HeadersOnly(t *testing.T) {
+	now := time.Now()
+	limiter := newRateLimiter(0, 1)
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, time.Duration(0), limiter.reserve(now))
+	}
+
+	limiter.update(&http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{headerRateLimitReset: []string{"30"}},
+	})
+
+	delay := limiter.reserve(time.Now())
+	assert.True(t, delay > 20*time.Second, "delay: %s", delay)
+}
+
+func TestRateLimiter_UpdateExhaustedQuota(t *testing.T) {
+	limiter := newRateLimiter(100, 10)
+
+	limiter.update(&http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			headerRateLimitRemaining: []string{"0"},
+			headerRateLimitReset:     []string{strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)},
+		},
+	})
+
+	delay := limiter.reserve(time.Now())
+	assert.True(t, delay > 50*time.Second, "delay: %s", delay)
+}
+
+func TestClient_RateLimitContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set(headerRateLimitRemaining, "0")
+		rw.Header().Set(headerRateLimitReset, "60")
+	}))
+	defer server.Close()
+
+	client := NewClient("test", "test", WithCustomHost(server.URL+"/"), WithRateLimit(100, 10))
+
+	r := &Request{
+		Path:   "/some/test/path",
+		Method: requestGet,
+	}
+
+	_, err := client.Send(r)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = client.SendContext(ctx, r)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
@@ -0,0 +1,161 @@
+package gomarsys
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts = 4
+	defaultRetryMinBackoff  = 500 * time.Millisecond
+	defaultRetryMaxBackoff  = 30 * time.Second
+)
+
+// RetryPolicy describes how Client retries failed requests. Rate-limited
+// responses (429) are retried for every method, transport errors and 5xx
+// responses only for idempotent methods (GET and PUT).
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	MaxAttempts int
+	// MinBackoff is the base delay, doubled after every failed attempt.
+	MinBackoff time.Duration
+	// MaxBackoff caps the computed delay. A Retry-After header sent by
+	// the server takes precedence over the computed delay.
+	MaxBackoff time.Duration
+}
+
+type RetryError struct {
+	attempts int
+	err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%s (attempts: %d)", e.err.Error(), e.attempts)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.err
+}
+
+// Attempts returns how many requests were sent before giving up.
+func (e *RetryError) Attempts() int {
+	return e.attempts
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: defaultRetryMaxAttempts,
+		MinBackoff:  defaultRetryMinBackoff,
+		MaxBackoff:  defaultRetryMaxBackoff,
+	}
+}
+
+// WithRetryPolicy enables retries. Errors returned by a client with a retry
+// policy are wrapped in *RetryError.
+func WithRetryPolicy(policy RetryPolicy) ClientOptions {
+	return func(options *options) {
+		if policy.MaxAttempts < 1 {
+			policy.MaxAttempts = 1
+		}
+		if policy.MaxBackoff < policy.MinBackoff {
+			policy.MaxBackoff = policy.MinBackoff
+		}
+
+		options.retryPolicy = &policy
+	}
+}
+
+func (p *RetryPolicy) shouldRetry(method string, resp *http.Response) bool {
+	idempotent := method == http.MethodGet || method == http.MethodPut
+
+	if resp == nil {
+		return idempotent
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return idempotent
+	}
+
+	return false
+}
+
+// backoff returns the delay before the given attempt (starting from 1 for the
+// first retry) using exponential growth with equal jitter.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return d
+		}
+	}
+
+	d := p.MaxBackoff
+	if attempt < 32 {
+		if exp := p.MinBackoff << uint(attempt-1); exp > 0 && exp < p.MaxBackoff {
+			d = exp
+		}
+	}
+
+	half := int64(d / 2)
+
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
+
+func (c *Client) doWithRetries(ctx context.Context, serverUrl string, r *Request) (io.ReadCloser, error) {
+	policy := c.retryPolicy
+	method := r.Method.String()
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.do(ctx, serverUrl, r)
+		if err == nil {
+			return newResponseBody(resp), nil
+		}
+
+		if ctx.Err() != nil || attempt >= policy.MaxAttempts || !policy.shouldRetry(method, resp) {
+			return nil, &RetryError{attempts: attempt, err: err}
+		}
+
+		if err := sleepContext(ctx, policy.backoff(attempt, resp)); err != nil {
+			return nil, &RetryError{attempts: attempt, err: err}
+		}
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
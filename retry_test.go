@@ -0,0 +1,91 @@
+package gomarsys
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  5 * time.Millisecond,
+	}
+}
+
+func TestClient_RetryIdempotent(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = rw.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	client := NewClient("test", "test", WithCustomHost(server.URL+"/"), WithRetryPolicy(testRetryPolicy()))
+
+	response, err := client.Send(&Request{Path: "/some/test/path", Method: requestGet})
+	require.NoError(t, err)
+	assert.Equal(t, "OK", string(response))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestClient_RetryNotIdempotent(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient("test", "test", WithCustomHost(server.URL+"/"), WithRetryPolicy(testRetryPolicy()))
+
+	_, err := client.Send(&Request{Path: "/some/test/path", Method: requestPost})
+	require.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	var retryError *RetryError
+	require.True(t, errors.As(err, &retryError))
+	assert.Equal(t, 1, retryError.Attempts())
+}
+
+func TestClient_RetryRateLimited(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		rw.Header().Set("Retry-After", "0")
+		rw.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient("test", "test", WithCustomHost(server.URL+"/"), WithRetryPolicy(testRetryPolicy()))
+
+	_, err := client.Send(&Request{Path: "/some/test/path", Method: requestPost})
+	require.Error(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	var retryError *RetryError
+	require.True(t, errors.As(err, &retryError))
+	assert.Equal(t, 3, retryError.Attempts())
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for attempt := 1; attempt < 10; attempt++ {
+		d := policy.backoff(attempt, nil)
+		assert.True(t, d >= 50*time.Millisecond && d <= time.Second, "attempt %d: %s", attempt, d)
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+	assert.Equal(t, 7*time.Second, policy.backoff(1, resp))
+}
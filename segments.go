@@ -0,0 +1,405 @@
+package gomarsys
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	SegmentRunStatusWaiting    = "waiting"
+	SegmentRunStatusInProgress = "in_progress"
+	SegmentRunStatusDone       = "done"
+	SegmentRunStatusError      = "error"
+
+	SegmentOperatorEquals      = "equals"
+	SegmentOperatorNotEquals   = "not_equals"
+	SegmentOperatorContains    = "contains"
+	SegmentOperatorStartsWith  = "starts_with"
+	SegmentOperatorEndsWith    = "ends_with"
+	SegmentOperatorIsEmpty     = "is_empty"
+	SegmentOperatorIsNotEmpty  = "is_not_empty"
+	SegmentOperatorGreaterThan = "greater_than"
+	SegmentOperatorLessThan    = "less_than"
+
+	defaultSegmentPageSize = 10000
+)
+
+type Segments struct {
+	client       ClientInterface
+	statusPeriod time.Duration
+}
+
+type Segment struct {
+	ID        int
+	Name      string
+	Timestamp string
+	Baseline  string
+}
+
+// SegmentCriterion is a single condition on a contact field.
+type SegmentCriterion struct {
+	FieldID  int
+	Operator string
+	Value    string
+}
+
+// SegmentDefinition describes a simple segment: contacts matching all
+// criteria, optionally restricted to a contact list.
+type SegmentDefinition struct {
+	Name              string
+	BaseContactListID int
+	Criteria          []SegmentCriterion
+}
+
+type SegmentRun struct {
+	RunID      string
+	Status     string
+	ContactIDs []string
+	TotalCount int
+}
+
+// SegmentRunError is returned when a segment run ends in the error state.
+type SegmentRunError struct {
+	RunID string
+	Run   *SegmentRun
+}
+
+func (e *SegmentRunError) Error() string {
+	return fmt.Sprintf("segment run failed, run id: %s", e.RunID)
+}
+
+// SegmentWaitError is returned when the run is not done within the maximum
+// wait. Run is the last status received, if any.
+type SegmentWaitError struct {
+	RunID string
+	Run   *SegmentRun
+	err   error
+}
+
+func (e *SegmentWaitError) Error() string {
+	return fmt.Sprintf("segment run not done, run id: %s: %s", e.RunID, e.err)
+}
+
+func (e *SegmentWaitError) Unwrap() error {
+	return e.err
+}
+
+func NewSegments(client ClientInterface) *Segments {
+	return &Segments{
+		client:       client,
+		statusPeriod: emarsysUpdateStatusPeriod,
+	}
+}
+
+func (s *Segments) List() ([]Segment, error) {
+	return s.ListContext(context.Background())
+}
+
+func (s *Segments) ListContext(ctx context.Context) ([]Segment, error) {
+	r := &Request{
+		Path:   "/v2/filter",
+		Method: requestGet,
+	}
+
+	var res struct {
+		ReplyCode int    `json:"replyCode"`
+		ReplyText string `json:"replyText"`
+		Data      []struct {
+			ID        flexibleID `json:"id"`
+			Name      string     `json:"name"`
+			Timestamp string     `json:"timestamp"`
+			Baseline  string     `json:"baseline"`
+		} `json:"data"`
+	}
+
+	if err := sendJSON(ctx, s.client, r, &res); err != nil {
+		return nil, err
+	}
+
+	segments := make([]Segment, len(res.Data))
+	for i, item := range res.Data {
+		id, err := strconv.Atoi(string(item.ID))
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse segment id '%s': %w", item.ID, err)
+		}
+
+		segments[i] = Segment{
+			ID:        id,
+			Name:      item.Name,
+			Timestamp: item.Timestamp,
+			Baseline:  item.Baseline,
+		}
+	}
+
+	return segments, nil
+}
+
+func (s *Segments) Create(definition SegmentDefinition) (int, error) {
+	return s.CreateContext(context.Background(), definition)
+}
+
+// CreateContext creates a segment and returns its id.
+func (s *Segments) CreateContext(ctx context.Context, definition SegmentDefinition) (int, error) {
+	type criterion struct {
+		Type     string `json:"type"`
+		Field    string `json:"field"`
+		Operator string `json:"operator"`
+		Value    string `json:"value,omitempty"`
+	}
+
+	type criteria struct {
+		Type     string      `json:"type"`
+		Children []criterion `json:"children"`
+	}
+
+	type request struct {
+		Name              string   `json:"name"`
+		BaseContactListID int      `json:"baseContactListId,omitempty"`
+		ContactCriteria   criteria `json:"contactCriteria"`
+	}
+
+	pr := &request{
+		Name:              definition.Name,
+		BaseContactListID: definition.BaseContactListID,
+		ContactCriteria: criteria{
+			Type:     "and",
+			Children: make([]criterion, len(definition.Criteria)),
+		},
+	}
+
+	for i, c := range definition.Criteria {
+		pr.ContactCriteria.Children[i] = criterion{
+			Type:     "criteria",
+			Field:    strconv.Itoa(c.FieldID),
+			Operator: c.Operator,
+			Value:    c.Value,
+		}
+	}
+
+	data, err := json.Marshal(pr)
+	if err != nil {
+		return 0, err
+	}
+
+	r := &Request{
+		Path:   "/v2/filter",
+		Method: requestPost,
+		Body:   data,
+	}
+
+	var res struct {
+		ReplyCode int    `json:"replyCode"`
+		ReplyText string `json:"replyText"`
+		Data      struct {
+			ID flexibleID `json:"id"`
+		} `json:"data"`
+	}
+
+	if err := sendJSON(ctx, s.client, r, &res); err != nil {
+		return 0, err
+	}
+
+	id, err := strconv.Atoi(string(res.Data.ID))
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse segment id '%s': %w", res.Data.ID, err)
+	}
+
+	return id, nil
+}
+
+func (s *Segments) Run(segmentID int) (*SegmentRun, error) {
+	return s.RunContext(context.Background(), segmentID)
+}
+
+// RunContext starts evaluating a segment. The result becomes available once
+// the run reaches SegmentRunStatusDone, see WaitRun.
+func (s *Segments) RunContext(ctx context.Context, segmentID int) (*SegmentRun, error) {
+	r := &Request{
+		Path:   fmt.Sprintf("/v2/filter/%d/runs", segmentID),
+		Method: requestPost,
+	}
+
+	return s.run(ctx, r)
+}
+
+func (s *Segments) RunStatus(runID string) (*SegmentRun, error) {
+	return s.RunStatusContext(context.Background(), runID)
+}
+
+func (s *Segments) RunStatusContext(ctx context.Context, runID string) (*SegmentRun, error) {
+	r := &Request{
+		Path:   fmt.Sprintf("/v2/filter/runs/%s", url.PathEscape(runID)),
+		Method: requestGet,
+	}
+
+	return s.run(ctx, r)
+}
+
+// WaitRun polls the run until it is done or failed. A run ending in the error
+// state is reported as a *SegmentRunError. The first check is immediate unless
+// WithWaitInitialDelay is given; the status callbacks of WaitOptions are for
+// exports only and are not called.
+func (s *Segments) WaitRun(ctx context.Context, runID string, opts ...WaitOptions) (*SegmentRun, error) {
+	o := newWaitOptions(0, s.statusPeriod, opts)
+
+	waitCtx := ctx
+	if o.maxWait > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, o.maxWait)
+		defer cancel()
+	}
+
+	var previous *SegmentRun
+
+	// only the expiry of maxWait is a SegmentWaitError, not the caller's
+	// context ending
+	waitError := func(err error) error {
+		if o.maxWait > 0 && waitCtx.Err() != nil && ctx.Err() == nil {
+			return &SegmentWaitError{RunID: runID, Run: previous, err: waitCtx.Err()}
+		}
+		return err
+	}
+
+	delay := o.initialDelay
+	interval := o.interval
+
+	for {
+		if err := sleepContext(waitCtx, delay); err != nil {
+			return nil, waitError(err)
+		}
+
+		run, err := s.RunStatusContext(waitCtx, runID)
+		if err != nil {
+			return nil, waitError(err)
+		}
+
+		switch run.Status {
+		case SegmentRunStatusDone:
+			return run, nil
+		case SegmentRunStatusError:
+			return nil, &SegmentRunError{RunID: runID, Run: run}
+		}
+
+		previous = run
+		delay = interval
+		interval = o.next(interval)
+	}
+}
+
+func (s *Segments) ContactCount(segmentID int) (int, error) {
+	return s.ContactCountContext(context.Background(), segmentID)
+}
+
+func (s *Segments) ContactCountContext(ctx context.Context, segmentID int) (int, error) {
+	r := &Request{
+		Path:   fmt.Sprintf("/v2/filter/%d/contacts/count", segmentID),
+		Method: requestGet,
+	}
+
+	var res struct {
+		ReplyCode int    `json:"replyCode"`
+		ReplyText string `json:"replyText"`
+		Data      struct {
+			Count int `json:"count"`
+		} `json:"data"`
+	}
+
+	if err := sendJSON(ctx, s.client, r, &res); err != nil {
+		return 0, err
+	}
+
+	return res.Data.Count, nil
+}
+
+func (s *Segments) ContactIDs(segmentID, offset, limit int) ([]string, error) {
+	return s.ContactIDsContext(context.Background(), segmentID, offset, limit)
+}
+
+// ContactIDsContext returns a page of the internal ids of the contacts in the
+// segment. limit of 0 uses the default page size.
+func (s *Segments) ContactIDsContext(ctx context.Context, segmentID, offset, limit int) ([]string, error) {
+	if limit <= 0 {
+		limit = defaultSegmentPageSize
+	}
+
+	query := url.Values{}
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("limit", strconv.Itoa(limit))
+
+	path := &url.URL{
+		Path:     fmt.Sprintf("/v2/filter/%d/contacts", segmentID),
+		RawQuery: query.Encode(),
+	}
+
+	r := &Request{
+		Path:   path.String(),
+		Method: requestGet,
+	}
+
+	var res struct {
+		ReplyCode int    `json:"replyCode"`
+		ReplyText string `json:"replyText"`
+		Data      struct {
+			ContactIDs []flexibleID `json:"contact_ids"`
+		} `json:"data"`
+	}
+
+	if err := sendJSON(ctx, s.client, r, &res); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(res.Data.ContactIDs))
+	for i, id := range res.Data.ContactIDs {
+		ids[i] = string(id)
+	}
+
+	return ids, nil
+}
+
+func (s *Segments) Export(segmentID int, fields []int) (*ExportResult, error) {
+	return s.ExportContext(context.Background(), segmentID, fields)
+}
+
+// ExportContext starts a local export of the segment, the same way as
+// Users.GetSegmentLocally.
+func (s *Segments) ExportContext(ctx context.Context, segmentID int, fields []int) (*ExportResult, error) {
+	return NewUsers(s.client).GetSegmentContext(ctx, localSegmentRequest(segmentID, fields))
+}
+
+func (s *Segments) run(ctx context.Context, r *Request) (*SegmentRun, error) {
+	var res struct {
+		ReplyCode int    `json:"replyCode"`
+		ReplyText string `json:"replyText"`
+		Data      struct {
+			RunID  flexibleID `json:"run_id"`
+			Status string     `json:"status"`
+			Result *struct {
+				ContactIDs []flexibleID `json:"contact_ids"`
+				TotalCount int          `json:"total_count"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+
+	if err := sendJSON(ctx, s.client, r, &res); err != nil {
+		return nil, err
+	}
+
+	run := &SegmentRun{
+		RunID:  string(res.Data.RunID),
+		Status: res.Data.Status,
+	}
+
+	if res.Data.Result != nil {
+		run.TotalCount = res.Data.Result.TotalCount
+		run.ContactIDs = make([]string, len(res.Data.Result.ContactIDs))
+		for i, id := range res.Data.Result.ContactIDs {
+			run.ContactIDs[i] = string(id)
+		}
+	}
+
+	return run, nil
+}
@@ -0,0 +1,173 @@
+package gomarsys
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegments_List(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/filter")
+		assert.Equal(t, req.Method, RequestMethod(requestGet))
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":[{"id":100,"name":"Active customers","timestamp":"2020-01-01 10:00:00","baseline":"0"}]}`), nil)
+
+	segments := NewSegments(client)
+	list, err := segments.List()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, 100, list[0].ID)
+	assert.Equal(t, "Active customers", list[0].Name)
+}
+
+func TestSegments_StringIDs(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Method == requestGet
+	})).Return([]byte(`{"replyCode":0,"replyText":"OK","data":[{"id":"100","name":"Active customers"}]}`), nil)
+	client.(*ClientMock).On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Method == requestPost
+	})).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"id":"101"}}`), nil)
+
+	segments := NewSegments(client)
+	list, err := segments.List()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, 100, list[0].ID)
+
+	id, err := segments.Create(SegmentDefinition{Name: "Opted in"})
+	require.NoError(t, err)
+	assert.Equal(t, 101, id)
+}
+
+func TestSegments_Create(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/filter")
+		assert.Equal(t, req.Method, RequestMethod(requestPost))
+
+		var v struct {
+			Name            string `json:"name"`
+			ContactCriteria struct {
+				Type     string              `json:"type"`
+				Children []map[string]string `json:"children"`
+			} `json:"contactCriteria"`
+		}
+		require.NoError(t, json.Unmarshal(req.Body, &v))
+		assert.Equal(t, "Opted in", v.Name)
+		assert.Equal(t, "and", v.ContactCriteria.Type)
+		assert.Equal(t, []map[string]string{
+			{"type": "criteria", "field": "31", "operator": SegmentOperatorEquals, "value": "1"},
+		}, v.ContactCriteria.Children)
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"id":101}}`), nil)
+
+	segments := NewSegments(client)
+	id, err := segments.Create(SegmentDefinition{
+		Name: "Opted in",
+		Criteria: []SegmentCriterion{
+			{FieldID: OptIn, Operator: SegmentOperatorEquals, Value: "1"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 101, id)
+}
+
+func TestSegments_RunAndWait(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/filter/100/runs"
+	})).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"run_id":"abc","status":"waiting","result":null}}`), nil)
+	client.(*ClientMock).On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/filter/runs/abc"
+	})).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"run_id":"abc","status":"done","result":{"contact_ids":[1,2],"total_count":2}}}`), nil)
+
+	segments := NewSegments(client)
+	run, err := segments.Run(100)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", run.RunID)
+	assert.Equal(t, SegmentRunStatusWaiting, run.Status)
+
+	run, err = segments.WaitRun(context.Background(), run.RunID)
+	require.NoError(t, err)
+	assert.Equal(t, SegmentRunStatusDone, run.Status)
+	assert.Equal(t, []string{"1", "2"}, run.ContactIDs)
+	assert.Equal(t, 2, run.TotalCount)
+}
+
+func TestSegments_WaitRunError(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"run_id":"abc","status":"error","result":null}}`), nil)
+
+	segments := NewSegments(client)
+	_, err := segments.WaitRun(context.Background(), "abc")
+
+	var runErr *SegmentRunError
+	require.True(t, errors.As(err, &runErr))
+	assert.Equal(t, "abc", runErr.RunID)
+	assert.Equal(t, SegmentRunStatusError, runErr.Run.Status)
+}
+
+func TestSegments_WaitRunTimeout(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"run_id":"abc","status":"in_progress","result":null}}`), nil)
+
+	segments := NewSegments(client)
+	_, err := segments.WaitRun(context.Background(), "abc", WithWaitInterval(time.Millisecond), WithWaitTimeout(20*time.Millisecond))
+
+	var waitErr *SegmentWaitError
+	require.True(t, errors.As(err, &waitErr))
+	assert.Equal(t, "abc", waitErr.RunID)
+	require.NotNil(t, waitErr.Run)
+	assert.Equal(t, SegmentRunStatusInProgress, waitErr.Run.Status)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestSegments_ContactCountAndIDs(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/filter/100/contacts/count"
+	})).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"count":3}}`), nil)
+	client.(*ClientMock).On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/filter/100/contacts?limit=2&offset=2"
+	})).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"contact_ids":["3"]}}`), nil)
+
+	segments := NewSegments(client)
+	count, err := segments.ContactCount(100)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	ids, err := segments.ContactIDs(100, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3"}, ids)
+}
+
+func TestSegments_Export(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/export/filter")
+
+		var v SegmentRequest
+		require.NoError(t, json.Unmarshal(req.Body, &v))
+		assert.Equal(t, 100, v.Filter)
+		assert.Equal(t, DistributionMethodLocal, v.DistributionMethod)
+		assert.Equal(t, []int{EMail}, v.ContactFields)
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"id":123}}`), nil)
+
+	segments := NewSegments(client)
+	result, err := segments.Export(100, []int{EMail})
+	require.NoError(t, err)
+	assert.Equal(t, 123, result.Data.ID)
+}
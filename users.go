@@ -1,8 +1,11 @@
 package gomarsys
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strconv"
 	"time"
@@ -29,8 +32,6 @@ const (
 )
 
 const (
-	ErrorCodeContactNotFound = 2008
-
 	PlatformOriginAll             = "all"
 	PlatformDefaultOriginID       = "0"
 	ExportDistributionMethodLocal = "local"
@@ -39,11 +40,18 @@ const (
 )
 
 type Users struct {
-	client ClientInterface
+	client         ClientInterface
+	fields         *FieldRegistry
+	choices        *ChoiceTranslator
+	optInSourceID  int
+	hasOptInSource bool
 }
 
+type UsersOptions func(u *Users)
+
 type User struct {
 	ID       string
+	UID      string
 	SourceID string
 	Data     map[int]string
 }
@@ -51,6 +59,7 @@ type User struct {
 func (u *User) Clone() *User {
 	clone := &User{
 		ID:       u.ID,
+		UID:      u.UID,
 		SourceID: u.SourceID,
 	}
 
@@ -106,93 +115,227 @@ const (
 type UserError struct {
 	code    int
 	message string
+	err     error
 }
 
 func (e *UserError) Error() string {
 	return e.message
 }
 
+func (e *UserError) Unwrap() error {
+	return e.err
+}
+
 func (e *UserError) Code() int {
 	return e.code
 }
 
-func NewUsers(client ClientInterface) *Users {
-	return &Users{
-		client: client,
+func (e *UserError) Is(target error) bool {
+	return matchReplyCode(e.code, target)
+}
+
+// newRequestError wraps an error returned by the client for a failed request,
+// taking the code from the reply code of an *APIError, if any.
+func newRequestError(err error) *UserError {
+	userError := &UserError{message: err.Error(), err: err}
+
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		userError.code = apiError.ReplyCode
 	}
+
+	return userError
 }
 
-func (u *Users) Create(user User, keyID int) error {
-	type request struct {
-		KeyID    string              `json:"key_id"`
-		Contacts []map[string]string `json:"contacts"`
+// checkUserReply returns a *UserError wrapping an *APIError when a 200
+// response carries a non-zero replyCode.
+func checkUserReply(response []byte, r *Request) error {
+	if apiError := checkReplyCode(response, r); apiError != nil {
+		return &UserError{
+			message: fmt.Sprintf("error code returned from api: '%s'", response),
+			code:    apiError.ReplyCode,
+			err:     apiError,
+		}
 	}
 
-	pr := &request{
-		KeyID: fmt.Sprintf("%d", keyID),
+	return nil
+}
+
+// newContactError builds the error of a single contact reported in the errors
+// section of a response.
+func newContactError(code int, message string, errorsContent json.RawMessage, r *Request) *UserError {
+	return &UserError{
+		message: message,
+		code:    code,
+		err: &APIError{
+			StatusCode: http.StatusOK,
+			ReplyCode:  code,
+			ReplyText:  message,
+			Body:       errorsContent,
+			Path:       r.Path,
+			Method:     r.Method.String(),
+		},
 	}
+}
 
-	m := make(map[string]string)
-	for key, val := range user.Data {
-		m[fmt.Sprintf("%d", key)] = val
+// WithFieldRegistry lets Users resolve field names of the account, not only
+// system field names.
+func WithFieldRegistry(registry *FieldRegistry) UsersOptions {
+	return func(u *Users) {
+		u.fields = registry
 	}
-	pr.Contacts = append(pr.Contacts, m)
+}
 
-	data, err := json.Marshal(pr)
-	if err != nil {
-		return err
+// WithChoiceTranslator enables the ",label" option of `emarsys` struct tags
+// in CreateFrom, UpdateUserFrom and GetUserInfoInto. The translator's field
+// registry is also used to resolve field names.
+func WithChoiceTranslator(translator *ChoiceTranslator) UsersOptions {
+	return func(u *Users) {
+		u.choices = translator
+		if u.fields == nil {
+			u.fields = translator.registry
+		}
 	}
+}
 
-	r := &Request{
-		Path:   "/v2/contact",
-		Method: requestPost,
-		Body:   data,
+func NewUsers(client ClientInterface, usersOptions ...UsersOptions) *Users {
+	u := &Users{
+		client: client,
 	}
 
-	if _, err := u.client.Send(r); err != nil {
-		return err
+	for _, f := range usersOptions {
+		f(u)
 	}
 
-	return nil
+	return u
 }
 
-func (u *Users) UpdateUser(user User, keyID int) error {
-	type request struct {
-		KeyID    string              `json:"key_id"`
-		Contacts []map[string]string `json:"contacts"`
+func (u *Users) fieldResolver(ctx context.Context) (fieldResolver, error) {
+	if u.fields == nil {
+		return resolveSystemField, nil
 	}
 
-	pr := &request{
-		KeyID: fmt.Sprintf("%d", keyID),
+	if err := u.fields.Load(ctx); err != nil {
+		return nil, err
 	}
 
-	m := make(map[string]string)
-	for key, val := range user.Data {
-		m[fmt.Sprintf("%d", key)] = val
+	return u.fields.resolve, nil
+}
+
+func (u *Users) fieldCodec(ctx context.Context) (*fieldCodec, error) {
+	resolve, err := u.fieldResolver(ctx)
+	if err != nil {
+		return nil, err
 	}
-	pr.Contacts = append(pr.Contacts, m)
 
-	data, err := json.Marshal(pr)
+	c := &fieldCodec{resolve: resolve}
+
+	if u.choices != nil {
+		c.labels = func(fieldID int, value string) ([]string, error) {
+			return u.choices.Labels(ctx, fieldID, value)
+		}
+		c.value = func(fieldID int, labels []string) (string, error) {
+			return u.choices.Value(ctx, fieldID, labels)
+		}
+	}
+
+	return c, nil
+}
+
+func (u *Users) resolveFieldNames(ctx context.Context, names []string) ([]int, error) {
+	resolve, err := u.fieldResolver(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	r := &Request{
-		Path:   "/v2/contact",
-		Method: requestPut,
-		Body:   data,
+	ids := make([]int, len(names))
+	for i, name := range names {
+		if id, err := strconv.Atoi(name); err == nil {
+			ids[i] = id
+		} else if id, ok := resolve(name); ok {
+			ids[i] = id
+		} else {
+			return nil, &UserError{message: fmt.Sprintf("unknown field '%s'", name)}
+		}
 	}
 
-	if _, err := u.client.Send(r); err != nil {
-		return err
+	return ids, nil
+}
+
+// Create creates a contact and returns its internal id.
+func (u *Users) Create(user User, keyID int) (string, error) {
+	return u.CreateContext(context.Background(), user, keyID)
+}
+
+func (u *Users) CreateContext(ctx context.Context, user User, keyID int) (string, error) {
+	return u.sendContact(ctx, requestPost, user, keyID)
+}
+
+// UpdateUser updates a contact and returns its internal id.
+func (u *Users) UpdateUser(user User, keyID int) (string, error) {
+	return u.UpdateUserContext(context.Background(), user, keyID)
+}
+
+func (u *Users) UpdateUserContext(ctx context.Context, user User, keyID int) (string, error) {
+	return u.sendContact(ctx, requestPut, user, keyID)
+}
+
+// CreateFrom creates a contact from a struct with `emarsys` tags, see Marshal.
+func (u *Users) CreateFrom(v interface{}, keyID int) (string, error) {
+	return u.CreateFromContext(context.Background(), v, keyID)
+}
+
+func (u *Users) CreateFromContext(ctx context.Context, v interface{}, keyID int) (string, error) {
+	c, err := u.fieldCodec(ctx)
+	if err != nil {
+		return "", err
 	}
 
-	return nil
+	data, err := marshal(v, c)
+	if err != nil {
+		return "", &UserError{message: err.Error()}
+	}
+
+	return u.CreateContext(ctx, User{Data: data}, keyID)
+}
+
+// UpdateUserFrom updates a contact from a struct with `emarsys` tags, see
+// Marshal.
+func (u *Users) UpdateUserFrom(v interface{}, keyID int) (string, error) {
+	return u.UpdateUserFromContext(context.Background(), v, keyID)
+}
+
+func (u *Users) UpdateUserFromContext(ctx context.Context, v interface{}, keyID int) (string, error) {
+	c, err := u.fieldCodec(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := marshal(v, c)
+	if err != nil {
+		return "", &UserError{message: err.Error()}
+	}
+
+	return u.UpdateUserContext(ctx, User{Data: data}, keyID)
+}
+
+func (u *Users) sendContact(ctx context.Context, method RequestMethod, user User, keyID int) (string, error) {
+	results := make([]ContactResult, 1)
+
+	if err := u.sendContacts(ctx, method, "/v2/contact", []User{user}, keyID, results); err != nil {
+		return "", err
+	}
+
+	return results[0].ID, results[0].Err
 }
 
 // Delete implements call to delete contact emarsys api method
 // https://dev.emarsys.com/v2/contacts/delete-contact
 func (u *Users) Delete(keyID int, keyValue string) error {
+	return u.DeleteContext(context.Background(), keyID, keyValue)
+}
+
+func (u *Users) DeleteContext(ctx context.Context, keyID int, keyValue string) error {
 	keyIDString := strconv.Itoa(keyID)
 	pr := map[string]string{
 		"key_id":    keyIDString,
@@ -215,19 +358,22 @@ func (u *Users) Delete(keyID int, keyValue string) error {
 		ReplyText string `json:"replyText"`
 		Data      struct {
 			Errors          json.RawMessage `json:"errors"`
-			DeletedContacts uint8           `json:"deleted_contacts"`
+			DeletedContacts int             `json:"deleted_contacts"`
 		} `json:"data"`
 	}
 
-	if response, err := u.client.Send(r); err != nil {
-		return err
+	if response, err := u.client.SendContext(ctx, r); err != nil {
+		return newRequestError(err)
 	} else {
+		if err := checkUserReply(response, r); err != nil {
+			return err
+		}
 		if err := json.Unmarshal(response, &res); err != nil {
 			return &UserError{message: err.Error()}
 		}
 	}
 
-	err = u.handleDeleteErrors(res.Data.Errors)
+	err = u.handleDeleteErrors(res.Data.Errors, r)
 	if err != nil {
 		return err
 	}
@@ -239,7 +385,7 @@ func (u *Users) Delete(keyID int, keyValue string) error {
 	return nil
 }
 
-func (u *Users) handleDeleteErrors(errorsContent json.RawMessage) error {
+func (u *Users) handleDeleteErrors(errorsContent json.RawMessage, r *Request) error {
 	// Response example:  `{"replyCode":0,"replyText":"OK","data":{"errors":{"":{"2005":"No value provided for key field: 3"}},"deleted_contacts":0}}`
 	var errorSlice []struct {
 		Key       string `json:"key"`
@@ -252,14 +398,14 @@ func (u *Users) handleDeleteErrors(errorsContent json.RawMessage) error {
 
 	if err := json.Unmarshal(errorsContent, &errorSlice); err == nil {
 		if len(errorSlice) > 0 {
-			return &UserError{message: errorSlice[0].ErrorMsg, code: errorSlice[0].ErrorCode}
+			return newContactError(errorSlice[0].ErrorCode, errorSlice[0].ErrorMsg, errorsContent, r)
 		}
 	} else if err := json.Unmarshal(errorsContent, &errorMap); err == nil {
 		if len(errorMap) > 0 {
 			for _, errorList := range errorMap {
 				for errorCodeString, errorMessage := range errorList {
 					errorCode, _ := strconv.Atoi(errorCodeString)
-					return &UserError{message: errorMessage, code: errorCode}
+					return newContactError(errorCode, errorMessage, errorsContent, r)
 				}
 			}
 		}
@@ -271,10 +417,62 @@ func (u *Users) handleDeleteErrors(errorsContent json.RawMessage) error {
 }
 
 func (u *Users) GetUserInfo(keyID int, keyValue string, fields []int) (*User, error) {
-	return u.GetUserInfoByKey(strconv.Itoa(keyID), keyValue, fields)
+	return u.GetUserInfoContext(context.Background(), keyID, keyValue, fields)
+}
+
+func (u *Users) GetUserInfoContext(ctx context.Context, keyID int, keyValue string, fields []int) (*User, error) {
+	return u.GetUserInfoByKeyContext(ctx, strconv.Itoa(keyID), keyValue, fields)
+}
+
+// GetUserInfoInto fetches the fields tagged on the struct v points to and
+// fills them, see Unmarshal. It returns the internal id of the contact.
+func (u *Users) GetUserInfoInto(keyID int, keyValue string, v interface{}) (string, error) {
+	return u.GetUserInfoIntoContext(context.Background(), keyID, keyValue, v)
+}
+
+func (u *Users) GetUserInfoIntoContext(ctx context.Context, keyID int, keyValue string, v interface{}) (string, error) {
+	c, err := u.fieldCodec(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	fields, err := fieldIDs(v, c.resolve)
+	if err != nil {
+		return "", &UserError{message: err.Error()}
+	}
+
+	user, err := u.GetUserInfoContext(ctx, keyID, keyValue, fields)
+	if err != nil {
+		return "", err
+	}
+
+	if err := unmarshal(user.Data, v, c); err != nil {
+		return "", &UserError{message: err.Error()}
+	}
+
+	return user.ID, nil
+}
+
+// GetUserInfoByNames is GetUserInfoByKey with the key and fields given as
+// field names or string ids, resolved through the field registry.
+func (u *Users) GetUserInfoByNames(key, keyValue string, fields []string) (*User, error) {
+	return u.GetUserInfoByNamesContext(context.Background(), key, keyValue, fields)
+}
+
+func (u *Users) GetUserInfoByNamesContext(ctx context.Context, key, keyValue string, fields []string) (*User, error) {
+	ids, err := u.resolveFieldNames(ctx, append([]string{key}, fields...))
+	if err != nil {
+		return nil, err
+	}
+
+	return u.GetUserInfoByKeyContext(ctx, strconv.Itoa(ids[0]), keyValue, ids[1:])
 }
 
 func (u *Users) GetUserInfoByKey(key, keyValue string, fields []int) (*User, error) {
+	return u.GetUserInfoByKeyContext(context.Background(), key, keyValue, fields)
+}
+
+func (u *Users) GetUserInfoByKeyContext(ctx context.Context, key, keyValue string, fields []int) (*User, error) {
 	type request struct {
 		KeyID     string   `json:"keyId"`
 		KeyValues []string `json:"keyValues"`
@@ -316,19 +514,20 @@ func (u *Users) GetUserInfoByKey(key, keyValue string, fields []int) (*User, err
 		} `json:"data"`
 	}
 
-	if response, err := u.client.Send(r); err != nil {
-		return nil, err
+	if response, err := u.client.SendContext(ctx, r); err != nil {
+		return nil, newRequestError(err)
 	} else {
+		if err := checkUserReply(response, r); err != nil {
+			return nil, err
+		}
 		if err := json.Unmarshal(response, &userData); err != nil {
 			return nil, &UserError{message: err.Error()}
 		}
 	}
 
-	user := &User{}
-	user.Data = make(map[int]string)
-
 	if len(userData.Data.Errors) > 0 {
-		return user, &UserError{message: userData.Data.Errors[0].ErrorMsg, code: userData.Data.Errors[0].ErrorCode}
+		user := &User{Data: make(map[int]string)}
+		return user, newContactError(userData.Data.Errors[0].ErrorCode, userData.Data.Errors[0].ErrorMsg, nil, r)
 	}
 
 	var result []map[string]*string
@@ -336,14 +535,25 @@ func (u *Users) GetUserInfoByKey(key, keyValue string, fields []int) (*User, err
 		return nil, &UserError{message: "empty user response"}
 	}
 
-	if len(userData.Data.Result) == 0 {
+	if len(result) == 0 {
 		return nil, &UserError{message: "empty user response"}
 	}
 
-	for key, value := range result[0] {
+	return parseUserRow(result[0])
+}
+
+// parseUserRow converts a row of a getdata result into a User.
+func parseUserRow(row map[string]*string) (*User, error) {
+	user := &User{}
+	user.Data = make(map[int]string)
+
+	for key, value := range row {
 		if key == "id" && value != nil {
 			user.ID = *value
 		}
+		if key == "uid" && value != nil {
+			user.UID = *value
+		}
 		if key == "uid" || key == "id" {
 			continue
 		}
@@ -362,6 +572,10 @@ func (u *Users) GetUserInfoByKey(key, keyValue string, fields []int) (*User, err
 }
 
 func (u *Users) ListUserIDs(keyID int, keyValue string) ([]string, error) {
+	return u.ListUserIDsContext(context.Background(), keyID, keyValue)
+}
+
+func (u *Users) ListUserIDsContext(ctx context.Context, keyID int, keyValue string) ([]string, error) {
 	query := url.Values{}
 	key := strconv.Itoa(keyID)
 
@@ -394,16 +608,19 @@ func (u *Users) ListUserIDs(keyID int, keyValue string) ([]string, error) {
 		} `json:"data"`
 	}
 
-	if response, err := u.client.Send(r); err != nil {
-		return nil, err
+	if response, err := u.client.SendContext(ctx, r); err != nil {
+		return nil, newRequestError(err)
 	} else {
+		if err := checkUserReply(response, r); err != nil {
+			return nil, err
+		}
 		if err := json.Unmarshal(response, &userData); err != nil {
 			return nil, &UserError{message: err.Error()}
 		}
 	}
 
 	if len(userData.Data.Errors) > 0 {
-		return nil, &UserError{message: userData.Data.Errors[0].ErrorMsg, code: userData.Data.Errors[0].ErrorCode}
+		return nil, newContactError(userData.Data.Errors[0].ErrorCode, userData.Data.Errors[0].ErrorMsg, nil, r)
 	}
 
 	ids := make([]string, len(userData.Data.Result))
@@ -419,60 +636,30 @@ func (u *Users) ListUserIDs(keyID int, keyValue string) ([]string, error) {
 }
 
 func (u *Users) MergeUsers(key string, sourceKeyValue, targetKeyValue string, overwriteFields []string) error {
-	type request struct {
-		KeyID          string            `json:"key_id"`
-		SourceKeyValue string            `json:"source_key_value"`
-		TargetKeyValue string            `json:"target_key_value"`
-		MergeRules     map[string]string `json:"merge_rules"`
-		DeleteSource   bool              `json:"delete_source"`
-	}
-
-	pr := &request{
-		KeyID:          key,
-		SourceKeyValue: sourceKeyValue,
-		TargetKeyValue: targetKeyValue,
-		MergeRules:     make(map[string]string, len(overwriteFields)),
-		DeleteSource:   true,
-	}
+	return u.MergeUsersContext(context.Background(), key, sourceKeyValue, targetKeyValue, overwriteFields)
+}
 
+func (u *Users) MergeUsersContext(ctx context.Context, key string, sourceKeyValue, targetKeyValue string, overwriteFields []string) error {
+	rules := make(map[string]MergeRule, len(overwriteFields))
 	for _, field := range overwriteFields {
-		pr.MergeRules[field] = "overwrite"
+		rules[field] = MergeRuleOverwrite
 	}
 
-	data, err := json.Marshal(pr)
-	if err != nil {
-		return &UserError{message: err.Error()}
-	}
-
-	r := &Request{
-		Path:   "/v2/contact/merge",
-		Method: requestPost,
-		Body:   data,
-	}
+	_, err := u.merge(ctx, key, sourceKeyValue, targetKeyValue, &mergeOptions{rules: rules})
 
-	var userData struct {
-		ReplyCode int    `json:"replyCode"`
-		ReplyText string `json:"replyText"`
-	}
-
-	response, err := u.client.Send(r)
-	if err != nil {
-		return err
-	}
-
-	if err := json.Unmarshal(response, &userData); err != nil {
-		return &UserError{message: err.Error()}
-	}
+	return err
+}
 
-	if userData.ReplyCode != 0 {
-		return &UserError{message: fmt.Sprintf("error code returned from api: '%s'", response)}
-	}
+func (u *Users) GetSegmentLocally(segmentID int, fields []int) (*ExportResult, error) {
+	return u.GetSegmentLocallyContext(context.Background(), segmentID, fields)
+}
 
-	return nil
+func (u *Users) GetSegmentLocallyContext(ctx context.Context, segmentID int, fields []int) (*ExportResult, error) {
+	return u.GetSegmentContext(ctx, localSegmentRequest(segmentID, fields))
 }
 
-func (u *Users) GetSegmentLocally(segmentID int, fields []int) (*ExportResult, error) {
-	return u.GetSegment(SegmentRequest{
+func localSegmentRequest(segmentID int, fields []int) SegmentRequest {
+	return SegmentRequest{
 		BaseExportRequest: BaseExportRequest{
 			DistributionMethod:  ExportDistributionMethodLocal,
 			ContactFields:       fields,
@@ -480,11 +667,15 @@ func (u *Users) GetSegmentLocally(segmentID int, fields []int) (*ExportResult, e
 			Delimiter:           defaultCSVDelimiter,
 		},
 		Filter: segmentID,
-	})
+	}
 }
 
 func (u *Users) GetAllChangesLocally(startTime, endTime time.Time, fields []int) (*ExportResult, error) {
-	return u.GetChanges(ChangesRequest{
+	return u.GetAllChangesLocallyContext(context.Background(), startTime, endTime, fields)
+}
+
+func (u *Users) GetAllChangesLocallyContext(ctx context.Context, startTime, endTime time.Time, fields []int) (*ExportResult, error) {
+	return u.GetChangesContext(ctx, ChangesRequest{
 		BaseExportRequest: BaseExportRequest{
 			DistributionMethod:  ExportDistributionMethodLocal,
 			ContactFields:       fields,
@@ -501,6 +692,10 @@ func (u *Users) GetAllChangesLocally(startTime, endTime time.Time, fields []int)
 }
 
 func (u *Users) GetChanges(request ChangesRequest) (*ExportResult, error) {
+	return u.GetChangesContext(context.Background(), request)
+}
+
+func (u *Users) GetChangesContext(ctx context.Context, request ChangesRequest) (*ExportResult, error) {
 	data, err := json.Marshal(request)
 	if err != nil {
 		return nil, err
@@ -514,9 +709,12 @@ func (u *Users) GetChanges(request ChangesRequest) (*ExportResult, error) {
 
 	status := &ExportResult{}
 
-	if response, err := u.client.Send(r); err != nil {
-		return nil, err
+	if response, err := u.client.SendContext(ctx, r); err != nil {
+		return nil, newRequestError(err)
 	} else {
+		if err := checkUserReply(response, r); err != nil {
+			return nil, err
+		}
 		err := json.Unmarshal(response, status)
 		if err != nil {
 			return nil, err
@@ -527,6 +725,10 @@ func (u *Users) GetChanges(request ChangesRequest) (*ExportResult, error) {
 }
 
 func (u *Users) GetContacts(request ContactRequest) (*ExportResult, error) {
+	return u.GetContactsContext(context.Background(), request)
+}
+
+func (u *Users) GetContactsContext(ctx context.Context, request ContactRequest) (*ExportResult, error) {
 	data, err := json.Marshal(request)
 	if err != nil {
 		return nil, err
@@ -540,9 +742,12 @@ func (u *Users) GetContacts(request ContactRequest) (*ExportResult, error) {
 
 	status := &ExportResult{}
 
-	if response, err := u.client.Send(r); err != nil {
-		return nil, err
+	if response, err := u.client.SendContext(ctx, r); err != nil {
+		return nil, newRequestError(err)
 	} else {
+		if err := checkUserReply(response, r); err != nil {
+			return nil, err
+		}
 		err := json.Unmarshal(response, status)
 		if err != nil {
 			return nil, err
@@ -553,6 +758,10 @@ func (u *Users) GetContacts(request ContactRequest) (*ExportResult, error) {
 }
 
 func (u *Users) GetSegment(request SegmentRequest) (*ExportResult, error) {
+	return u.GetSegmentContext(context.Background(), request)
+}
+
+func (u *Users) GetSegmentContext(ctx context.Context, request SegmentRequest) (*ExportResult, error) {
 	data, err := json.Marshal(request)
 	if err != nil {
 		return nil, err
@@ -566,9 +775,12 @@ func (u *Users) GetSegment(request SegmentRequest) (*ExportResult, error) {
 
 	status := &ExportResult{}
 
-	if response, err := u.client.Send(r); err != nil {
-		return nil, err
+	if response, err := u.client.SendContext(ctx, r); err != nil {
+		return nil, newRequestError(err)
 	} else {
+		if err := checkUserReply(response, r); err != nil {
+			return nil, err
+		}
 		err := json.Unmarshal(response, status)
 		if err != nil {
 			return nil, err
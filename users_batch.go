@@ -0,0 +1,502 @@
+package gomarsys
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	maxContactsPerRequest   = 1000
+	defaultBatchConcurrency = 4
+)
+
+type BatchOptions func(o *batchOptions)
+
+type batchOptions struct {
+	size        int
+	concurrency int
+}
+
+// WithBatchSize sets how many contacts are sent per request, capped at the
+// Emarsys limit of 1000.
+func WithBatchSize(size int) BatchOptions {
+	return func(o *batchOptions) {
+		if size > 0 && size <= maxContactsPerRequest {
+			o.size = size
+		}
+	}
+}
+
+// WithBatchConcurrency sets how many requests of a batch run in parallel.
+func WithBatchConcurrency(concurrency int) BatchOptions {
+	return func(o *batchOptions) {
+		if concurrency > 0 {
+			o.concurrency = concurrency
+		}
+	}
+}
+
+// ContactResult is the outcome for a single contact of a batch operation.
+// KeyValue is the value of the key field the contact was identified by.
+type ContactResult struct {
+	KeyValue string
+	ID       string
+	Err      error
+}
+
+// flexibleID accepts ids sent either as numbers or as strings.
+type flexibleID string
+
+func (id *flexibleID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = flexibleID(s)
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+
+	*id = flexibleID(n.String())
+
+	return nil
+}
+
+type contactsResponse struct {
+	ReplyCode int    `json:"replyCode"`
+	ReplyText string `json:"replyText"`
+	Data      struct {
+		IDs    []flexibleID    `json:"ids"`
+		Errors json.RawMessage `json:"errors"`
+	} `json:"data"`
+}
+
+func (u *Users) CreateMany(users []User, keyID int, opts ...BatchOptions) ([]ContactResult, error) {
+	return u.CreateManyContext(context.Background(), users, keyID, opts...)
+}
+
+// CreateManyContext creates contacts in chunks and returns one result per
+// contact in input order. The error is the first request level failure; the
+// contacts of a failed request carry that error in their result as well.
+func (u *Users) CreateManyContext(ctx context.Context, users []User, keyID int, opts ...BatchOptions) ([]ContactResult, error) {
+	return u.sendContactsBatched(ctx, requestPost, "/v2/contact", users, keyID, opts)
+}
+
+func (u *Users) UpdateMany(users []User, keyID int, opts ...BatchOptions) ([]ContactResult, error) {
+	return u.UpdateManyContext(context.Background(), users, keyID, opts...)
+}
+
+// UpdateManyContext updates contacts in chunks, see CreateManyContext.
+func (u *Users) UpdateManyContext(ctx context.Context, users []User, keyID int, opts ...BatchOptions) ([]ContactResult, error) {
+	return u.sendContactsBatched(ctx, requestPut, "/v2/contact", users, keyID, opts)
+}
+
+func (u *Users) sendContactsBatched(ctx context.Context, method RequestMethod, path string, users []User, keyID int, opts []BatchOptions) ([]ContactResult, error) {
+	results := make([]ContactResult, len(users))
+
+	err := runChunks(ctx, len(users), newBatchOptions(opts), func(start, end int) error {
+		return u.sendContacts(ctx, method, path, users[start:end], keyID, results[start:end])
+	}, func(start, end int, err error) {
+		for i := start; i < end; i++ {
+			results[i] = ContactResult{KeyValue: users[i].Data[keyID], Err: err}
+		}
+	})
+
+	return results, err
+}
+
+func newBatchOptions(opts []BatchOptions) *batchOptions {
+	o := &batchOptions{
+		size:        maxContactsPerRequest,
+		concurrency: defaultBatchConcurrency,
+	}
+
+	for _, f := range opts {
+		f(o)
+	}
+
+	return o
+}
+
+// runChunks calls send for consecutive chunks of n items, running at most
+// o.concurrency of them at once, and returns the first error. Once ctx is
+// cancelled the remaining items are passed to skip instead.
+func runChunks(ctx context.Context, n int, o *batchOptions, send func(start, end int) error, skip func(start, end int, err error)) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	setErr := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		mu.Unlock()
+	}
+
+	sem := make(chan struct{}, o.concurrency)
+
+	for start := 0; start < n; start += o.size {
+		end := start + o.size
+		if end > n {
+			end = n
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			setErr(ctx.Err())
+			if skip != nil {
+				skip(start, n, ctx.Err())
+			}
+			wg.Wait()
+
+			return firstErr
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := send(start, end); err != nil {
+				setErr(err)
+			}
+		}(start, end)
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
+
+// sendContacts sends a single request for the given contacts and fills
+// results, which must have the same length as users.
+func (u *Users) sendContacts(ctx context.Context, method RequestMethod, path string, users []User, keyID int, results []ContactResult) error {
+	for i, user := range users {
+		results[i] = ContactResult{KeyValue: user.Data[keyID]}
+	}
+
+	fail := func(err error) error {
+		for i := range results {
+			results[i].Err = err
+		}
+		return err
+	}
+
+	data, err := contactsRequestBody(users, keyID)
+	if err != nil {
+		return fail(err)
+	}
+
+	r := &Request{
+		Path:   path,
+		Method: method,
+		Body:   data,
+	}
+
+	var res contactsResponse
+
+	if response, err := u.client.SendContext(ctx, r); err != nil {
+		return fail(newRequestError(err))
+	} else {
+		if err := checkUserReply(response, r); err != nil {
+			return fail(err)
+		}
+		if err := json.Unmarshal(response, &res); err != nil {
+			return fail(&UserError{message: err.Error()})
+		}
+	}
+
+	contactErrors, err := parseContactErrors(res.Data.Errors, r)
+	if err != nil {
+		return fail(err)
+	}
+
+	// Emarsys may return the key value of an error in a different case than
+	// sent, e.g. for emails, so errors are matched to the contacts case-insensitively
+	requested := make(map[string][]int, len(results))
+	for i := range results {
+		folded := strings.ToLower(results[i].KeyValue)
+		requested[folded] = append(requested[folded], i)
+	}
+
+	var unmatched []string
+	for keyValue, contactErr := range contactErrors {
+		indexes, ok := requested[strings.ToLower(keyValue)]
+		if !ok {
+			unmatched = append(unmatched, keyValue)
+			continue
+		}
+		for _, i := range indexes {
+			results[i].Err = contactErr
+		}
+	}
+
+	// a single contact owns every error of the response, whatever its key
+	if len(results) == 1 && results[0].Err == nil && len(unmatched) > 0 {
+		sort.Strings(unmatched)
+		results[0].Err = contactErrors[unmatched[0]]
+		return nil
+	}
+
+	// the ids are given in order for the contacts without an error, so they
+	// can't be assigned when an error can't be matched to its contact
+	if len(unmatched) > 0 {
+		sort.Strings(unmatched)
+		unknownErr := &UserError{message: fmt.Sprintf("contact outcome unknown, errors reported for unknown key values: %q", unmatched)}
+		for i := range results {
+			if results[i].Err == nil {
+				results[i].Err = unknownErr
+			}
+		}
+		return nil
+	}
+
+	next := 0
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+
+		if next < len(res.Data.IDs) {
+			results[i].ID = string(res.Data.IDs[next])
+			next++
+		}
+	}
+
+	return nil
+}
+
+func contactsRequestBody(users []User, keyID int) ([]byte, error) {
+	type request struct {
+		KeyID    string              `json:"key_id"`
+		Contacts []map[string]string `json:"contacts"`
+	}
+
+	pr := &request{
+		KeyID:    fmt.Sprintf("%d", keyID),
+		Contacts: make([]map[string]string, 0, len(users)),
+	}
+
+	for _, user := range users {
+		m := make(map[string]string, len(user.Data))
+		for key, val := range user.Data {
+			m[fmt.Sprintf("%d", key)] = val
+		}
+		pr.Contacts = append(pr.Contacts, m)
+	}
+
+	return json.Marshal(pr)
+}
+
+// parseContactErrors decodes the errors section of contact responses, which
+// Emarsys sends either as a list or as a map of key value to code and message.
+func parseContactErrors(errorsContent json.RawMessage, r *Request) (map[string]*UserError, error) {
+	result := make(map[string]*UserError)
+
+	if len(errorsContent) == 0 || string(errorsContent) == "null" {
+		return result, nil
+	}
+
+	var errorSlice []struct {
+		Key       string `json:"key"`
+		ErrorCode int    `json:"errorCode"`
+		ErrorMsg  string `json:"errorMsg"`
+	}
+
+	var errorMap map[string]map[string]string
+
+	if err := json.Unmarshal(errorsContent, &errorSlice); err == nil {
+		for _, item := range errorSlice {
+			result[item.Key] = newContactError(item.ErrorCode, item.ErrorMsg, errorsContent, r)
+		}
+	} else if err := json.Unmarshal(errorsContent, &errorMap); err == nil {
+		for key, errorList := range errorMap {
+			for errorCodeString, errorMessage := range errorList {
+				errorCode, _ := strconv.Atoi(errorCodeString)
+				result[key] = newContactError(errorCode, errorMessage, errorsContent, r)
+			}
+		}
+	} else {
+		return nil, &UserError{message: fmt.Sprintf("unknown format of errors in response: '%s'", string(errorsContent))}
+	}
+
+	return result, nil
+}
+
+// UsersInfo is the result of GetUsersInfo.
+type UsersInfo struct {
+	// Users holds the found contacts by key value.
+	Users map[string]*User
+	// NotFound lists the key values no contact exists for, in input order.
+	NotFound []string
+	// Errors holds other per-key errors by key value.
+	Errors map[string]error
+}
+
+func (u *Users) GetUsersInfo(key string, keyValues []string, fields []int, opts ...BatchOptions) (*UsersInfo, error) {
+	return u.GetUsersInfoContext(context.Background(), key, keyValues, fields, opts...)
+}
+
+// GetUsersInfoContext fetches many contacts by key value in chunks. key is a
+// field id or a field name. The returned error is the first request level
+// failure only; per-key outcomes are reported in UsersInfo.
+func (u *Users) GetUsersInfoContext(ctx context.Context, key string, keyValues []string, fields []int, opts ...BatchOptions) (*UsersInfo, error) {
+	keyIDs, err := u.resolveFieldNames(ctx, []string{key})
+	if err != nil {
+		return nil, err
+	}
+
+	keyID := keyIDs[0]
+
+	requestFields := []int{keyID}
+	for _, f := range fields {
+		if f != keyID {
+			requestFields = append(requestFields, f)
+		}
+	}
+
+	info := &UsersInfo{
+		Users:  make(map[string]*User, len(keyValues)),
+		Errors: make(map[string]error),
+	}
+
+	var mu sync.Mutex
+
+	err = runChunks(ctx, len(keyValues), newBatchOptions(opts), func(start, end int) error {
+		users, contactErrors, err := u.getUsersData(ctx, keyID, keyValues[start:end], requestFields)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		for keyValue, user := range users {
+			info.Users[keyValue] = user
+		}
+
+		for keyValue, contactErr := range contactErrors {
+			if contactErr.Code() != ErrorCodeContactNotFound {
+				info.Errors[keyValue] = contactErr
+			}
+		}
+
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, keyValue := range keyValues {
+		if _, ok := info.Users[keyValue]; ok {
+			continue
+		}
+		if _, ok := info.Errors[keyValue]; ok {
+			continue
+		}
+		info.NotFound = append(info.NotFound, keyValue)
+	}
+
+	return info, nil
+}
+
+func (u *Users) getUsersData(ctx context.Context, keyID int, keyValues []string, fields []int) (map[string]*User, map[string]*UserError, error) {
+	type request struct {
+		KeyID     string   `json:"keyId"`
+		KeyValues []string `json:"keyValues"`
+		Fields    []string `json:"fields"`
+	}
+
+	key := strconv.Itoa(keyID)
+
+	pr := &request{
+		KeyID:     key,
+		KeyValues: keyValues,
+		Fields:    make([]string, len(fields)),
+	}
+
+	for i, f := range fields {
+		pr.Fields[i] = strconv.Itoa(f)
+	}
+
+	data, err := json.Marshal(pr)
+	if err != nil {
+		return nil, nil, &UserError{message: err.Error()}
+	}
+
+	r := &Request{
+		Path:   "/v2/contact/getdata",
+		Method: requestPost,
+		Body:   data,
+	}
+
+	var userData struct {
+		ReplyCode int    `json:"replyCode"`
+		ReplyText string `json:"replyText"`
+		Data      struct {
+			Errors json.RawMessage `json:"errors"`
+			Result json.RawMessage `json:"result,omitempty"`
+		} `json:"data"`
+	}
+
+	if response, err := u.client.SendContext(ctx, r); err != nil {
+		return nil, nil, newRequestError(err)
+	} else {
+		if err := checkUserReply(response, r); err != nil {
+			return nil, nil, err
+		}
+		if err := json.Unmarshal(response, &userData); err != nil {
+			return nil, nil, &UserError{message: err.Error()}
+		}
+	}
+
+	contactErrors, err := parseContactErrors(userData.Data.Errors, r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	users := make(map[string]*User, len(keyValues))
+
+	// result is `false` when none of the key values were found
+	var result []map[string]*string
+	if err := json.Unmarshal(userData.Data.Result, &result); err != nil {
+		return users, contactErrors, nil
+	}
+
+	// Emarsys may return the key value in a different case than requested,
+	// e.g. for emails, so rows are mapped back to the requested values
+	requested := make(map[string][]string, len(keyValues))
+	for _, keyValue := range keyValues {
+		folded := strings.ToLower(keyValue)
+		requested[folded] = append(requested[folded], keyValue)
+	}
+
+	for _, row := range result {
+		user, err := parseUserRow(row)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		keyValue, ok := user.Data[keyID]
+		if !ok {
+			continue
+		}
+
+		for _, requestedValue := range requested[strings.ToLower(keyValue)] {
+			users[requestedValue] = user
+		}
+	}
+
+	return users, contactErrors, nil
+}
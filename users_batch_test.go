@@ -0,0 +1,180 @@
+package gomarsys
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsers_CreateMany(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests int
+	)
+
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/contact")
+		assert.Equal(t, req.Method, RequestMethod(requestPost))
+
+		var v struct {
+			KeyID    string              `json:"key_id"`
+			Contacts []map[string]string `json:"contacts"`
+		}
+		require.NoError(t, json.Unmarshal(req.Body, &v))
+		assert.Equal(t, fmt.Sprintf("%d", EMail), v.KeyID)
+		assert.Len(t, v.Contacts, 2)
+
+		mu.Lock()
+		requests++
+		mu.Unlock()
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"ids":[101],"errors":{"b@test.ru":{"2009":"Contact with the external id already exists: 3"}}}}`), nil)
+
+	users := []User{
+		{Data: map[int]string{EMail: "a@test.ru"}},
+		{Data: map[int]string{EMail: "b@test.ru"}},
+		{Data: map[int]string{EMail: "a@test.ru"}},
+		{Data: map[int]string{EMail: "b@test.ru"}},
+	}
+
+	user := NewUsers(client)
+	results, err := user.CreateMany(users, EMail, WithBatchSize(2), WithBatchConcurrency(2))
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+	require.Len(t, results, 4)
+
+	for i := 0; i < 4; i += 2 {
+		assert.Equal(t, "a@test.ru", results[i].KeyValue)
+		assert.Equal(t, "101", results[i].ID)
+		assert.NoError(t, results[i].Err)
+
+		assert.Equal(t, "b@test.ru", results[i+1].KeyValue)
+		assert.Empty(t, results[i+1].ID)
+		assert.True(t, errors.Is(results[i+1].Err, ErrDuplicateContact))
+	}
+}
+
+func TestUsers_CreateManyErrorKeyCase(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).
+		Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"ids":[102,103],"errors":{"a@test.ru":{"2009":"Contact with the external id already exists: 3"}}}}`), nil)
+
+	user := NewUsers(client)
+	results, err := user.CreateMany([]User{
+		{Data: map[int]string{EMail: "A@Test.ru"}},
+		{Data: map[int]string{EMail: "b@test.ru"}},
+		{Data: map[int]string{EMail: "c@test.ru"}},
+	}, EMail)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "A@Test.ru", results[0].KeyValue)
+	assert.Empty(t, results[0].ID)
+	assert.True(t, errors.Is(results[0].Err, ErrDuplicateContact))
+
+	assert.Equal(t, "102", results[1].ID)
+	assert.NoError(t, results[1].Err)
+	assert.Equal(t, "103", results[2].ID)
+	assert.NoError(t, results[2].Err)
+}
+
+func TestUsers_CreateManyUnmatchedError(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).
+		Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"ids":[102],"errors":{"x@test.ru":{"2009":"Contact with the external id already exists: 3"}}}}`), nil)
+
+	user := NewUsers(client)
+	results, err := user.CreateMany([]User{
+		{Data: map[int]string{EMail: "a@test.ru"}},
+		{Data: map[int]string{EMail: "b@test.ru"}},
+	}, EMail)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	for _, result := range results {
+		assert.Empty(t, result.ID)
+		require.Error(t, result.Err)
+		assert.Contains(t, result.Err.Error(), "x@test.ru")
+	}
+}
+
+func TestUsers_UpdateManyRequestError(t *testing.T) {
+	apiError := &APIError{StatusCode: 500}
+
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/contact")
+		assert.Equal(t, req.Method, RequestMethod(requestPut))
+	}).Return([]byte(nil), apiError)
+
+	user := NewUsers(client)
+	results, err := user.UpdateMany([]User{
+		{Data: map[int]string{EMail: "a@test.ru"}},
+	}, EMail)
+	require.Error(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, err, results[0].Err)
+
+	var userError *UserError
+	require.True(t, errors.As(err, &userError))
+	assert.Equal(t, apiError, userError.Unwrap())
+}
+
+func TestUsers_GetUsersInfo(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/contact/getdata")
+		assert.Equal(t, req.Method, RequestMethod(requestPost))
+
+		var v struct {
+			KeyID     string   `json:"keyId"`
+			KeyValues []string `json:"keyValues"`
+			Fields    []string `json:"fields"`
+		}
+		require.NoError(t, json.Unmarshal(req.Body, &v))
+		assert.Equal(t, "3", v.KeyID)
+		assert.Equal(t, []string{"a@test.ru", "b@test.ru", "c@test.ru"}, v.KeyValues)
+		assert.Equal(t, []string{"3", "1"}, v.Fields)
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"errors":[`+
+		`{"key":"b@test.ru","errorCode":2008,"errorMsg":"No contact found with the external id: 3"},`+
+		`{"key":"c@test.ru","errorCode":2010,"errorMsg":"More than one contact found with the external id: 3"}],`+
+		`"result":[{"3":"a@test.ru","1":"A","id":"101","uid":"x"}]}}`), nil)
+
+	user := NewUsers(client)
+	info, err := user.GetUsersInfo("email", []string{"a@test.ru", "b@test.ru", "c@test.ru"}, []int{EMail, FirstName})
+	require.NoError(t, err)
+
+	require.Contains(t, info.Users, "a@test.ru")
+	assert.Equal(t, "101", info.Users["a@test.ru"].ID)
+	assert.Equal(t, "A", info.Users["a@test.ru"].Data[FirstName])
+	assert.Equal(t, []string{"b@test.ru"}, info.NotFound)
+	require.Contains(t, info.Errors, "c@test.ru")
+	assert.True(t, errors.Is(info.Errors["c@test.ru"], ErrAmbiguousContact))
+}
+
+func TestUsers_GetUsersInfoKeyCase(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).
+		Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"errors":[],"result":[{"3":"a@test.ru","id":"101"}]}}`), nil)
+
+	user := NewUsers(client)
+	info, err := user.GetUsersInfo("email", []string{"A@test.ru"}, nil)
+	require.NoError(t, err)
+
+	require.Contains(t, info.Users, "A@test.ru")
+	assert.Equal(t, "101", info.Users["A@test.ru"].ID)
+	assert.NotContains(t, info.Users, "a@test.ru")
+	assert.Empty(t, info.NotFound)
+}
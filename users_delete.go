@@ -0,0 +1,142 @@
+package gomarsys
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type DeleteStatus int
+
+const (
+	DeleteStatusDeleted DeleteStatus = iota + 1
+	// DeleteStatusNotFound means there was no contact to delete.
+	DeleteStatusNotFound
+	DeleteStatusFailed
+)
+
+// DeleteResult is the outcome of deleting a single contact. Err is set for
+// DeleteStatusNotFound and DeleteStatusFailed.
+type DeleteResult struct {
+	KeyValue string
+	Status   DeleteStatus
+	Err      error
+}
+
+func (u *Users) DeleteMany(keyID int, keyValues []string, opts ...BatchOptions) ([]DeleteResult, error) {
+	return u.DeleteManyContext(context.Background(), keyID, keyValues, opts...)
+}
+
+// DeleteManyContext deletes contacts in chunks and returns one result per key
+// value in input order. The error is the first request level failure; the
+// contacts of a failed request are reported as DeleteStatusFailed.
+func (u *Users) DeleteManyContext(ctx context.Context, keyID int, keyValues []string, opts ...BatchOptions) ([]DeleteResult, error) {
+	results := make([]DeleteResult, len(keyValues))
+
+	err := runChunks(ctx, len(keyValues), newBatchOptions(opts), func(start, end int) error {
+		return u.deleteContacts(ctx, keyID, keyValues[start:end], results[start:end])
+	}, func(start, end int, err error) {
+		for i := start; i < end; i++ {
+			results[i] = DeleteResult{KeyValue: keyValues[i], Status: DeleteStatusFailed, Err: err}
+		}
+	})
+
+	return results, err
+}
+
+func (u *Users) deleteContacts(ctx context.Context, keyID int, keyValues []string, results []DeleteResult) error {
+	fail := func(err error) error {
+		for i, keyValue := range keyValues {
+			results[i] = DeleteResult{KeyValue: keyValue, Status: DeleteStatusFailed, Err: err}
+		}
+		return err
+	}
+
+	type request struct {
+		KeyID    string              `json:"key_id"`
+		Contacts []map[string]string `json:"contacts"`
+	}
+
+	key := strconv.Itoa(keyID)
+
+	pr := &request{
+		KeyID:    key,
+		Contacts: make([]map[string]string, len(keyValues)),
+	}
+
+	for i, keyValue := range keyValues {
+		pr.Contacts[i] = map[string]string{key: keyValue}
+	}
+
+	data, err := json.Marshal(pr)
+	if err != nil {
+		return fail(&UserError{message: err.Error()})
+	}
+
+	r := &Request{
+		Path:   "/v2/contact/delete",
+		Method: requestPost,
+		Body:   data,
+	}
+
+	var res struct {
+		ReplyCode int    `json:"replyCode"`
+		ReplyText string `json:"replyText"`
+		Data      struct {
+			Errors          json.RawMessage `json:"errors"`
+			DeletedContacts int             `json:"deleted_contacts"`
+		} `json:"data"`
+	}
+
+	if response, err := u.client.SendContext(ctx, r); err != nil {
+		return fail(newRequestError(err))
+	} else {
+		if err := checkUserReply(response, r); err != nil {
+			return fail(err)
+		}
+		if err := json.Unmarshal(response, &res); err != nil {
+			return fail(&UserError{message: err.Error()})
+		}
+	}
+
+	contactErrors, err := parseContactErrors(res.Data.Errors, r)
+	if err != nil {
+		return fail(err)
+	}
+
+	// Emarsys may return the key value of an error in a different case than
+	// sent, e.g. for emails, so errors are matched case-insensitively
+	foldedErrors := make(map[string]*UserError, len(contactErrors))
+	for keyValue, contactErr := range contactErrors {
+		foldedErrors[strings.ToLower(keyValue)] = contactErr
+	}
+
+	deleted := make(map[string]struct{}, len(keyValues))
+
+	for i, keyValue := range keyValues {
+		results[i] = DeleteResult{KeyValue: keyValue, Status: DeleteStatusDeleted}
+
+		contactErr, ok := foldedErrors[strings.ToLower(keyValue)]
+		if !ok {
+			deleted[strings.ToLower(keyValue)] = struct{}{}
+			continue
+		}
+
+		results[i].Err = contactErr
+		if contactErr.Code() == ErrorCodeContactNotFound {
+			results[i].Status = DeleteStatusNotFound
+		} else {
+			results[i].Status = DeleteStatusFailed
+		}
+	}
+
+	// an error that can't be matched to its contact would leave the contact
+	// reported as deleted, so the outcome of the chunk is unknown
+	if len(deleted) != res.Data.DeletedContacts {
+		return fail(&UserError{message: fmt.Sprintf("%d contacts deleted, %d reported as deleted", len(deleted), res.Data.DeletedContacts)})
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package gomarsys
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsers_DeleteMany(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/contact/delete")
+		assert.Equal(t, req.Method, RequestMethod(requestPost))
+
+		var v struct {
+			KeyID    string              `json:"key_id"`
+			Contacts []map[string]string `json:"contacts"`
+		}
+		require.NoError(t, json.Unmarshal(req.Body, &v))
+		assert.Equal(t, "3", v.KeyID)
+		assert.Equal(t, []map[string]string{{"3": "a@test.ru"}, {"3": "b@test.ru"}, {"3": "c@test.ru"}}, v.Contacts)
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"errors":{`+
+		`"b@test.ru":{"2008":"No contact found with the external id: 3"},`+
+		`"c@test.ru":{"2010":"More than one contact found with the external id: 3"}},"deleted_contacts":1}}`), nil)
+
+	user := NewUsers(client)
+	results, err := user.DeleteMany(EMail, []string{"a@test.ru", "b@test.ru", "c@test.ru"})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, DeleteResult{KeyValue: "a@test.ru", Status: DeleteStatusDeleted}, results[0])
+	assert.Equal(t, DeleteStatusNotFound, results[1].Status)
+	assert.True(t, errors.Is(results[1].Err, ErrContactNotFound))
+	assert.Equal(t, DeleteStatusFailed, results[2].Status)
+	assert.True(t, errors.Is(results[2].Err, ErrAmbiguousContact))
+}
+
+func TestUsers_DeleteManyChunks(t *testing.T) {
+	chunkSize := func(size int) interface{} {
+		return mock.MatchedBy(func(r *Request) bool {
+			var v struct {
+				Contacts []map[string]string `json:"contacts"`
+			}
+			return json.Unmarshal(r.Body, &v) == nil && len(v.Contacts) == size
+		})
+	}
+
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", chunkSize(2)).
+		Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"errors":[],"deleted_contacts":2}}`), nil)
+	client.(*ClientMock).On("Send", chunkSize(1)).
+		Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"errors":[],"deleted_contacts":1}}`), nil)
+
+	keyValues := []string{"a", "b", "c", "d", "e"}
+
+	user := NewUsers(client)
+	results, err := user.DeleteMany(EMail, keyValues, WithBatchSize(2))
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+
+	for i, result := range results {
+		assert.Equal(t, keyValues[i], result.KeyValue)
+		assert.Equal(t, DeleteStatusDeleted, result.Status)
+	}
+
+	client.(*ClientMock).AssertNumberOfCalls(t, "Send", 3)
+}
+
+func TestUsers_DeleteManyErrorKeyCase(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).
+		Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"errors":{`+
+			`"b@test.ru":{"2008":"No contact found with the external id: 3"}},"deleted_contacts":1}}`), nil)
+
+	user := NewUsers(client)
+	results, err := user.DeleteMany(EMail, []string{"a@test.ru", "B@Test.ru"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, DeleteStatusDeleted, results[0].Status)
+	assert.Equal(t, DeleteStatusNotFound, results[1].Status)
+	assert.True(t, errors.Is(results[1].Err, ErrContactNotFound))
+}
+
+func TestUsers_DeleteManyCountMismatch(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).
+		Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"errors":{`+
+			`"x@test.ru":{"2008":"No contact found with the external id: 3"}},"deleted_contacts":1}}`), nil)
+
+	user := NewUsers(client)
+	results, err := user.DeleteMany(EMail, []string{"a@test.ru", "b@test.ru"})
+	require.Error(t, err)
+	require.Len(t, results, 2)
+
+	for _, result := range results {
+		assert.Equal(t, DeleteStatusFailed, result.Status)
+		assert.Equal(t, err, result.Err)
+	}
+}
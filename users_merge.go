@@ -0,0 +1,121 @@
+package gomarsys
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+// MergeRule is the strategy for a field when merging two contacts.
+type MergeRule string
+
+const (
+	// MergeRuleOverwrite takes the value of the source contact.
+	MergeRuleOverwrite MergeRule = "overwrite"
+	// MergeRuleKeep keeps the value of the target contact.
+	MergeRuleKeep MergeRule = "keep"
+)
+
+type MergeOptions func(o *mergeOptions)
+
+type mergeOptions struct {
+	rules      map[string]MergeRule
+	keepSource bool
+}
+
+// WithMergeRule sets the strategy for a field. Fields without a rule keep
+// the value of the target contact.
+func WithMergeRule(fieldID int, rule MergeRule) MergeOptions {
+	return func(o *mergeOptions) {
+		o.rules[strconv.Itoa(fieldID)] = rule
+	}
+}
+
+// WithKeepSource keeps the source contact after merging instead of deleting
+// it.
+func WithKeepSource() MergeOptions {
+	return func(o *mergeOptions) {
+		o.keepSource = true
+	}
+}
+
+// MergeResult is the outcome of Merge. ContactID is the internal id of the
+// resulting contact.
+type MergeResult struct {
+	ContactID string
+}
+
+func (u *Users) Merge(keyID int, sourceKeyValue, targetKeyValue string, opts ...MergeOptions) (*MergeResult, error) {
+	return u.MergeContext(context.Background(), keyID, sourceKeyValue, targetKeyValue, opts...)
+}
+
+// MergeContext merges the source contact into the target contact, both
+// identified by the keyID field.
+func (u *Users) MergeContext(ctx context.Context, keyID int, sourceKeyValue, targetKeyValue string, opts ...MergeOptions) (*MergeResult, error) {
+	o := &mergeOptions{
+		rules: make(map[string]MergeRule),
+	}
+
+	for _, f := range opts {
+		f(o)
+	}
+
+	return u.merge(ctx, strconv.Itoa(keyID), sourceKeyValue, targetKeyValue, o)
+}
+
+func (u *Users) merge(ctx context.Context, key string, sourceKeyValue, targetKeyValue string, o *mergeOptions) (*MergeResult, error) {
+	type request struct {
+		KeyID          string               `json:"key_id"`
+		SourceKeyValue string               `json:"source_key_value"`
+		TargetKeyValue string               `json:"target_key_value"`
+		MergeRules     map[string]MergeRule `json:"merge_rules"`
+		DeleteSource   bool                 `json:"delete_source"`
+	}
+
+	pr := &request{
+		KeyID:          key,
+		SourceKeyValue: sourceKeyValue,
+		TargetKeyValue: targetKeyValue,
+		MergeRules:     o.rules,
+		DeleteSource:   !o.keepSource,
+	}
+
+	data, err := json.Marshal(pr)
+	if err != nil {
+		return nil, &UserError{message: err.Error()}
+	}
+
+	r := &Request{
+		Path:   "/v2/contact/merge",
+		Method: requestPost,
+		Body:   data,
+	}
+
+	var userData struct {
+		ReplyCode int             `json:"replyCode"`
+		ReplyText string          `json:"replyText"`
+		Data      json.RawMessage `json:"data"`
+	}
+
+	response, err := u.client.SendContext(ctx, r)
+	if err != nil {
+		return nil, newRequestError(err)
+	}
+
+	if err := checkUserReply(response, r); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(response, &userData); err != nil {
+		return nil, &UserError{message: err.Error()}
+	}
+
+	var resultData struct {
+		ID flexibleID `json:"id"`
+	}
+
+	// data is not an object for responses which carry no contact id
+	_ = json.Unmarshal(userData.Data, &resultData)
+
+	return &MergeResult{ContactID: string(resultData.ID)}, nil
+}
@@ -0,0 +1,61 @@
+package gomarsys
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsers_Merge(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/contact/merge")
+		assert.Equal(t, req.Method, RequestMethod(requestPost))
+
+		var v struct {
+			KeyID          string            `json:"key_id"`
+			SourceKeyValue string            `json:"source_key_value"`
+			TargetKeyValue string            `json:"target_key_value"`
+			MergeRules     map[string]string `json:"merge_rules"`
+			DeleteSource   bool              `json:"delete_source"`
+		}
+		require.NoError(t, json.Unmarshal(req.Body, &v))
+		assert.Equal(t, "3", v.KeyID)
+		assert.Equal(t, "source@test.ru", v.SourceKeyValue)
+		assert.Equal(t, "target@test.ru", v.TargetKeyValue)
+		assert.Equal(t, map[string]string{"1": "overwrite", "31": "keep"}, v.MergeRules)
+		assert.False(t, v.DeleteSource)
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"id":111111}}`), nil)
+
+	user := NewUsers(client)
+	result, err := user.Merge(EMail, "source@test.ru", "target@test.ru",
+		WithMergeRule(FirstName, MergeRuleOverwrite),
+		WithMergeRule(OptIn, MergeRuleKeep),
+		WithKeepSource(),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "111111", result.ContactID)
+}
+
+func TestUsers_MergeUsers(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		var v struct {
+			MergeRules   map[string]string `json:"merge_rules"`
+			DeleteSource bool              `json:"delete_source"`
+		}
+		require.NoError(t, json.Unmarshal(req.Body, &v))
+		assert.Equal(t, map[string]string{"1": "overwrite"}, v.MergeRules)
+		assert.True(t, v.DeleteSource)
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":[]}`), nil)
+
+	user := NewUsers(client)
+	require.NoError(t, user.MergeUsers("3", "source@test.ru", "target@test.ru", []string{"1"}))
+}
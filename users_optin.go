@@ -0,0 +1,139 @@
+package gomarsys
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+type OptInStatus int
+
+const (
+	OptInUnknown OptInStatus = iota
+	OptInTrue
+	OptInFalse
+)
+
+// WithOptInSourceField sets the custom field SetOptIn writes the source of an
+// opt-in change to.
+func WithOptInSourceField(fieldID int) UsersOptions {
+	return func(u *Users) {
+		u.optInSourceID = fieldID
+		u.hasOptInSource = true
+	}
+}
+
+func (u *Users) SetOptIn(keyID int, keyValue string, optIn bool, source string) error {
+	return u.SetOptInContext(context.Background(), keyID, keyValue, optIn, source)
+}
+
+// SetOptInContext sets the opt-in status of a contact. A non-empty source is
+// stored in the field configured with WithOptInSourceField.
+func (u *Users) SetOptInContext(ctx context.Context, keyID int, keyValue string, optIn bool, source string) error {
+	user := User{Data: map[int]string{
+		keyID: keyValue,
+		OptIn: choiceFalse,
+	}}
+
+	if optIn {
+		user.Data[OptIn] = choiceTrue
+	}
+
+	if source != "" {
+		if !u.hasOptInSource {
+			return &UserError{message: "opt-in source field is not configured"}
+		}
+		user.Data[u.optInSourceID] = source
+	}
+
+	_, err := u.UpdateUserContext(ctx, user, keyID)
+
+	return err
+}
+
+func (u *Users) GetOptInStatuses(keyID int, keyValues []string) (map[string]OptInStatus, error) {
+	return u.GetOptInStatusesContext(context.Background(), keyID, keyValues)
+}
+
+// GetOptInStatusesContext returns the opt-in status by key value. Contacts
+// which were not found are left out; if some contacts could not be read the
+// statuses of the others are returned along with the first per-key error.
+func (u *Users) GetOptInStatusesContext(ctx context.Context, keyID int, keyValues []string) (map[string]OptInStatus, error) {
+	info, err := u.GetUsersInfoContext(ctx, strconv.Itoa(keyID), keyValues, []int{OptIn})
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[string]OptInStatus, len(info.Users))
+	for keyValue, user := range info.Users {
+		statuses[keyValue] = parseOptInStatus(user.Data[OptIn])
+	}
+
+	for _, keyValue := range keyValues {
+		if contactErr, ok := info.Errors[keyValue]; ok {
+			return statuses, contactErr
+		}
+	}
+
+	return statuses, nil
+}
+
+func (u *Users) UnsubscribeFromCampaign(launchListID, emailID int, contactUID string) error {
+	return u.UnsubscribeFromCampaignContext(context.Background(), launchListID, emailID, contactUID)
+}
+
+// UnsubscribeFromCampaignContext unsubscribes a contact, identified by its
+// uid (see User.UID), from the launch list of an email campaign.
+func (u *Users) UnsubscribeFromCampaignContext(ctx context.Context, launchListID, emailID int, contactUID string) error {
+	type request struct {
+		LaunchListID string `json:"launch_list_id"`
+		EmailID      string `json:"email_id"`
+		ContactUID   string `json:"contact_uid"`
+	}
+
+	data, err := json.Marshal(&request{
+		LaunchListID: strconv.Itoa(launchListID),
+		EmailID:      strconv.Itoa(emailID),
+		ContactUID:   contactUID,
+	})
+	if err != nil {
+		return &UserError{message: err.Error()}
+	}
+
+	r := &Request{
+		Path:   "/v2/email/unsubscribe",
+		Method: requestPost,
+		Body:   data,
+	}
+
+	var res struct {
+		ReplyCode int    `json:"replyCode"`
+		ReplyText string `json:"replyText"`
+	}
+
+	response, err := u.client.SendContext(ctx, r)
+	if err != nil {
+		return newRequestError(err)
+	}
+
+	if err := checkUserReply(response, r); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(response, &res); err != nil {
+		return &UserError{message: err.Error()}
+	}
+
+	return nil
+}
+
+func parseOptInStatus(value string) OptInStatus {
+	switch value {
+	case choiceTrue:
+		return OptInTrue
+	case choiceFalse:
+		return OptInFalse
+	}
+
+	return OptInUnknown
+}
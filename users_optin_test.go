@@ -0,0 +1,75 @@
+package gomarsys
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsers_SetOptIn(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/contact")
+		assert.Equal(t, req.Method, RequestMethod(requestPut))
+
+		var v struct {
+			Contacts []map[string]string `json:"contacts"`
+		}
+		require.NoError(t, json.Unmarshal(req.Body, &v))
+		assert.Equal(t, map[string]string{"3": "test@test.ru", "31": "1", "4321": "checkout"}, v.Contacts[0])
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"ids":[111111]}}`), nil)
+
+	user := NewUsers(client, WithOptInSourceField(4321))
+	require.NoError(t, user.SetOptIn(EMail, "test@test.ru", true, "checkout"))
+}
+
+func TestUsers_SetOptInWithoutSourceField(t *testing.T) {
+	user := NewUsers(NewClientMock())
+	require.Error(t, user.SetOptIn(EMail, "test@test.ru", false, "checkout"))
+}
+
+func TestUsers_GetOptInStatuses(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).
+		Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"errors":[{"key":"c@test.ru","errorCode":2008,"errorMsg":"No contact found with the external id: 3"}],`+
+			`"result":[{"3":"a@test.ru","31":"1","id":"1"},{"3":"b@test.ru","31":"2","id":"2"}]}}`), nil)
+
+	user := NewUsers(client)
+	statuses, err := user.GetOptInStatuses(EMail, []string{"a@test.ru", "b@test.ru", "c@test.ru"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]OptInStatus{"a@test.ru": OptInTrue, "b@test.ru": OptInFalse}, statuses)
+}
+
+func TestUsers_UnsubscribeFromCampaign(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/email/unsubscribe")
+		assert.Equal(t, req.Method, RequestMethod(requestPost))
+
+		var v map[string]string
+		require.NoError(t, json.Unmarshal(req.Body, &v))
+		assert.Equal(t, map[string]string{"launch_list_id": "10", "email_id": "20", "contact_uid": "fd90tidfpd"}, v)
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":""}`), nil)
+
+	user := NewUsers(client)
+	require.NoError(t, user.UnsubscribeFromCampaign(10, 20, "fd90tidfpd"))
+}
+
+func TestUsers_UnsubscribeFromCampaignError(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).
+		Return([]byte(`{"replyCode":2008,"replyText":"No contact found","data":""}`), nil)
+
+	user := NewUsers(client)
+	err := user.UnsubscribeFromCampaign(10, 20, "fd90tidfpd")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrContactNotFound))
+}
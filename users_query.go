@@ -0,0 +1,170 @@
+package gomarsys
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const defaultQueryPageSize = 1000
+
+// ContactQuery pages through the contacts whose key field matches a value:
+//
+//	q := users.QueryContacts(ctx, EMail, "test@test.ru", []int{FirstName}, 0)
+//	for q.Next() {
+//		user := q.Contact()
+//	}
+//	if err := q.Err(); err != nil {
+//	}
+type ContactQuery struct {
+	ctx      context.Context
+	users    *Users
+	keyID    int
+	keyValue string
+	fields   []int
+	pageSize int
+
+	page   []*User
+	pos    int
+	offset int
+	done   bool
+	err    error
+}
+
+// QueryContacts returns an iterator over contacts matching keyValue on the
+// keyID field, each holding the id and the values of fields. pageSize of 0
+// uses the default page size.
+func (u *Users) QueryContacts(ctx context.Context, keyID int, keyValue string, fields []int, pageSize int) *ContactQuery {
+	if pageSize <= 0 {
+		pageSize = defaultQueryPageSize
+	}
+
+	return &ContactQuery{
+		ctx:      ctx,
+		users:    u,
+		keyID:    keyID,
+		keyValue: keyValue,
+		fields:   fields,
+		pageSize: pageSize,
+	}
+}
+
+// Next advances to the next contact, fetching the next page when needed.
+func (q *ContactQuery) Next() bool {
+	if q.err != nil {
+		return false
+	}
+
+	q.pos++
+
+	for q.pos >= len(q.page) {
+		if q.done {
+			return false
+		}
+
+		if err := q.fetch(); err != nil {
+			q.err = err
+			return false
+		}
+	}
+
+	return true
+}
+
+// Contact returns the current contact.
+func (q *ContactQuery) Contact() *User {
+	if q.pos < 0 || q.pos >= len(q.page) {
+		return nil
+	}
+
+	return q.page[q.pos]
+}
+
+func (q *ContactQuery) Err() error {
+	return q.err
+}
+
+func (q *ContactQuery) fetch() error {
+	key := strconv.Itoa(q.keyID)
+
+	returnFields := make([]string, 0, len(q.fields)+1)
+	returnFields = append(returnFields, key)
+	for _, f := range q.fields {
+		if f != q.keyID {
+			returnFields = append(returnFields, strconv.Itoa(f))
+		}
+	}
+
+	query := url.Values{}
+	query.Set("return", strings.Join(returnFields, ","))
+	query.Set(key, q.keyValue)
+	query.Set("excludeempty", "false")
+	query.Set("offset", strconv.Itoa(q.offset))
+	query.Set("limit", strconv.Itoa(q.pageSize))
+
+	path := &url.URL{
+		Path:     "/v2/contact/query",
+		RawQuery: query.Encode(),
+	}
+
+	r := &Request{
+		Path:   path.String(),
+		Method: requestGet,
+	}
+
+	var userData struct {
+		ReplyCode int    `json:"replyCode"`
+		ReplyText string `json:"replyText"`
+		Data      struct {
+			Errors json.RawMessage          `json:"errors"`
+			Result []map[string]*flexibleID `json:"result,omitempty"`
+		} `json:"data"`
+	}
+
+	if response, err := q.users.client.SendContext(q.ctx, r); err != nil {
+		return newRequestError(err)
+	} else {
+		if err := checkUserReply(response, r); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(response, &userData); err != nil {
+			return &UserError{message: err.Error()}
+		}
+	}
+
+	contactErrors, err := parseContactErrors(userData.Data.Errors, r)
+	if err != nil {
+		return err
+	}
+
+	for _, contactErr := range contactErrors {
+		return contactErr
+	}
+
+	q.page = make([]*User, len(userData.Data.Result))
+	for i, row := range userData.Data.Result {
+		values := make(map[string]*string, len(row))
+		for k, v := range row {
+			if v != nil {
+				s := string(*v)
+				values[k] = &s
+			} else {
+				values[k] = nil
+			}
+		}
+
+		user, err := parseUserRow(values)
+		if err != nil {
+			return err
+		}
+		q.page[i] = user
+	}
+
+	q.pos = 0
+	q.offset += len(q.page)
+	q.done = len(q.page) < q.pageSize
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package gomarsys
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsers_QueryContacts(t *testing.T) {
+	queryOffset := func(offset string) interface{} {
+		return mock.MatchedBy(func(r *Request) bool {
+			u, err := url.Parse(r.Path)
+			require.NoError(t, err)
+
+			return u.Path == "/v2/contact/query" && u.Query().Get("offset") == offset
+		})
+	}
+
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", queryOffset("0")).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		u, err := url.Parse(req.Path)
+		require.NoError(t, err)
+		assert.Equal(t, req.Method, RequestMethod(requestGet))
+		assert.Equal(t, "31,3", u.Query().Get("return"))
+		assert.Equal(t, "1", u.Query().Get("31"))
+		assert.Equal(t, "2", u.Query().Get("limit"))
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"errors":[],"result":[{"id":"1","31":"1","3":"a@test.ru"},{"id":2,"31":"1","3":null}]}}`), nil)
+	client.(*ClientMock).On("Send", queryOffset("2")).
+		Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"errors":[],"result":[{"id":"3","31":"1","3":"c@test.ru"}]}}`), nil)
+
+	user := NewUsers(client)
+	q := user.QueryContacts(context.Background(), OptIn, "1", []int{EMail}, 2)
+
+	var contacts []*User
+	for q.Next() {
+		contacts = append(contacts, q.Contact())
+	}
+	require.NoError(t, q.Err())
+	require.Len(t, contacts, 3)
+
+	assert.Equal(t, "1", contacts[0].ID)
+	assert.Equal(t, "a@test.ru", contacts[0].Data[EMail])
+	assert.Equal(t, "2", contacts[1].ID)
+	assert.NotContains(t, contacts[1].Data, EMail)
+	assert.Equal(t, "3", contacts[2].ID)
+
+	client.(*ClientMock).AssertNumberOfCalls(t, "Send", 2)
+}
+
+func TestUsers_QueryContactsEmpty(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).
+		Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"errors":[],"result":[]}}`), nil)
+
+	user := NewUsers(client)
+	q := user.QueryContacts(context.Background(), EMail, "a@test.ru", nil, 0)
+
+	assert.False(t, q.Next())
+	assert.NoError(t, q.Err())
+	assert.Nil(t, q.Contact())
+}
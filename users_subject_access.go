@@ -0,0 +1,106 @@
+package gomarsys
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// SubjectAccessReport holds everything stored on the account about a single
+// contact, ready to be marshalled as a data subject access response.
+type SubjectAccessReport struct {
+	ContactID   string               `json:"contact_id"`
+	GeneratedAt time.Time            `json:"generated_at"`
+	Fields      []SubjectAccessField `json:"fields"`
+}
+
+// SubjectAccessField is a field with a value. Value is a string, or a
+// []string of labels for multi-choice fields.
+type SubjectAccessField struct {
+	ID       int         `json:"id"`
+	StringID string      `json:"string_id,omitempty"`
+	Name     string      `json:"name"`
+	Type     string      `json:"type"`
+	Value    interface{} `json:"value"`
+}
+
+// JSON returns the report as an indented JSON document.
+func (r *SubjectAccessReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+func (u *Users) GetSubjectAccessReport(keyID int, keyValue string) (*SubjectAccessReport, error) {
+	return u.GetSubjectAccessReportContext(context.Background(), keyID, keyValue)
+}
+
+// GetSubjectAccessReportContext fetches every field defined on the account
+// for the contact, with field names and choice labels resolved. Users without
+// a field registry or choice translator use ones built on their client.
+func (u *Users) GetSubjectAccessReportContext(ctx context.Context, keyID int, keyValue string) (*SubjectAccessReport, error) {
+	registry := u.fields
+	if registry == nil {
+		registry = NewFieldRegistry(NewFields(u.client))
+	}
+
+	choices := u.choices
+	if choices == nil {
+		choices = NewChoiceTranslator(registry)
+	}
+
+	fields, err := registry.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(fields, func(i, j int) bool { return fields[i].ID < fields[j].ID })
+
+	ids := make([]int, len(fields))
+	for i, field := range fields {
+		ids[i] = field.ID
+	}
+
+	user, err := u.GetUserInfoByKeyContext(ctx, strconv.Itoa(keyID), keyValue, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SubjectAccessReport{
+		ContactID:   user.ID,
+		GeneratedAt: time.Now().UTC(),
+		Fields:      make([]SubjectAccessField, 0, len(user.Data)),
+	}
+
+	for _, field := range fields {
+		value, ok := user.Data[field.ID]
+		if !ok || value == "" {
+			continue
+		}
+
+		item := SubjectAccessField{
+			ID:       field.ID,
+			StringID: field.StringID,
+			Name:     field.Name,
+			Type:     field.ApplicationType,
+			Value:    value,
+		}
+
+		if isChoiceType(field.ApplicationType) {
+			labels, err := choices.Labels(ctx, field.ID, value)
+			if err != nil {
+				return nil, err
+			}
+
+			if field.ApplicationType == FieldTypeMultiChoice {
+				item.Value = labels
+			} else if len(labels) > 0 {
+				item.Value = labels[0]
+			}
+		}
+
+		report.Fields = append(report.Fields, item)
+	}
+
+	return report, nil
+}
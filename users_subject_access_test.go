@@ -0,0 +1,44 @@
+package gomarsys
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsers_GetSubjectAccessReport(t *testing.T) {
+	client := newTestChoicesClient()
+	client.On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/contact/getdata"
+	})).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		var v struct {
+			Fields []string `json:"fields"`
+		}
+		require.NoError(t, json.Unmarshal(req.Body, &v))
+		assert.Equal(t, []string{"3", "5", "1234", "1235"}, v.Fields)
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"errors":[],"result":[{"3":"test@test.ru","5":"2","1234":null,"1235":"1,3","id":"111111","uid":"x"}]}}`), nil)
+
+	user := NewUsers(client)
+	report, err := user.GetSubjectAccessReport(EMail, "test@test.ru")
+	require.NoError(t, err)
+
+	assert.Equal(t, "111111", report.ContactID)
+	assert.Equal(t, []SubjectAccessField{
+		{ID: 3, StringID: "email", Name: "Email", Type: "email", Value: "test@test.ru"},
+		{ID: 5, StringID: "gender", Name: "Gender", Type: FieldTypeSingleChoice, Value: "Female"},
+		{ID: 1235, StringID: "favourite_brands", Name: "Favourite brands", Type: FieldTypeMultiChoice, Value: []string{"Acme", "Initech"}},
+	}, report.Fields)
+
+	document, err := report.JSON()
+	require.NoError(t, err)
+
+	var v map[string]interface{}
+	require.NoError(t, json.Unmarshal(document, &v))
+	assert.Equal(t, "111111", v["contact_id"])
+	assert.Len(t, v["fields"], 3)
+}
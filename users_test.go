@@ -2,6 +2,7 @@ package gomarsys
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -27,16 +28,52 @@ func TestUsers_Create(t *testing.T) {
 		assert.Equal(t, v.(map[string]interface{})["contacts"].([]interface{})[0].(map[string]interface{})[fmt.Sprintf("%d", FirstName)], "Test")
 		assert.Equal(t, v.(map[string]interface{})["contacts"].([]interface{})[0].(map[string]interface{})[fmt.Sprintf("%d", LastName)], "Test")
 		assert.Equal(t, v.(map[string]interface{})["contacts"].([]interface{})[0].(map[string]interface{})[fmt.Sprintf("%d", EMail)], "test@test.ru")
-	}).Return([]byte{}, nil)
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"ids":[111111]}}`), nil)
 
 	user := NewUsers(client)
-	user.Create(User{
+	id, err := user.Create(User{
 		Data: map[int]string{
 			FirstName: "Test",
 			LastName:  "Test",
 			EMail:     "test@test.ru",
 		},
 	}, EMail)
+	require.NoError(t, err)
+	assert.Equal(t, "111111", id)
+}
+
+func TestUsers_CreateContactError(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).
+		Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"ids":[],"errors":{"test@test.ru":{"2009":"Contact with the external id already exists: 3"}}}}`), nil)
+
+	user := NewUsers(client)
+	id, err := user.Create(User{
+		Data: map[int]string{
+			EMail: "test@test.ru",
+		},
+	}, EMail)
+	require.Error(t, err)
+	assert.Empty(t, id)
+	assert.True(t, errors.Is(err, ErrDuplicateContact))
+}
+
+func TestUsers_CreateContactErrorKeyCase(t *testing.T) {
+	for _, errorKey := range []string{"test@test.ru", "other@test.ru"} {
+		client := NewClientMock()
+		client.(*ClientMock).On("Send", mock.Anything).
+			Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"ids":[],"errors":{"`+errorKey+`":{"2009":"Contact with the external id already exists: 3"}}}}`), nil)
+
+		user := NewUsers(client)
+		id, err := user.Create(User{
+			Data: map[int]string{
+				EMail: "Test@Test.ru",
+			},
+		}, EMail)
+		require.Error(t, err, errorKey)
+		assert.Empty(t, id)
+		assert.True(t, errors.Is(err, ErrDuplicateContact), errorKey)
+	}
 }
 
 func TestUsers_UpdateUser(t *testing.T) {
@@ -54,16 +91,18 @@ func TestUsers_UpdateUser(t *testing.T) {
 		assert.Equal(t, v.(map[string]interface{})["contacts"].([]interface{})[0].(map[string]interface{})[fmt.Sprintf("%d", FirstName)], "Test")
 		assert.Equal(t, v.(map[string]interface{})["contacts"].([]interface{})[0].(map[string]interface{})[fmt.Sprintf("%d", LastName)], "Test")
 		assert.Equal(t, v.(map[string]interface{})["contacts"].([]interface{})[0].(map[string]interface{})[fmt.Sprintf("%d", EMail)], "test@test.ru")
-	}).Return([]byte{}, nil)
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"ids":["111111"]}}`), nil)
 
 	user := NewUsers(client)
-	user.UpdateUser(User{
+	id, err := user.UpdateUser(User{
 		Data: map[int]string{
 			FirstName: "Test",
 			LastName:  "Test",
 			EMail:     "test@test.ru",
 		},
 	}, EMail)
+	require.NoError(t, err)
+	assert.Equal(t, "111111", id)
 }
 
 func TestUsers_GetUserInfo(t *testing.T) {
@@ -203,4 +242,27 @@ func TestUsers_GetSegments(t *testing.T) {
 	assert.Equal(t, changes.ReplyCode, 0)
 	assert.Equal(t, changes.ReplyText, "ok")
 	assert.Equal(t, changes.Data.ID, 123)
-}
\ No newline at end of file
+}
+
+func TestUsers_MergeUsersReplyCode(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/contact/merge")
+		assert.Equal(t, req.Method, RequestMethod(requestPost))
+	}).Return([]byte(`{"replyCode":2008,"replyText":"No contact found with the external id: 3"}`), nil)
+
+	user := NewUsers(client)
+	err := user.MergeUsers("3", "source@test.ru", "target@test.ru", nil)
+	require.Error(t, err)
+
+	var userError *UserError
+	require.True(t, errors.As(err, &userError))
+	assert.Equal(t, 2008, userError.Code())
+
+	var apiError *APIError
+	require.True(t, errors.As(err, &apiError))
+	assert.Equal(t, 2008, apiError.ReplyCode)
+	assert.Equal(t, "/v2/contact/merge", apiError.Path)
+}
@@ -0,0 +1,146 @@
+package gomarsys
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// UpsertOutcome tells whether Upsert created or updated a contact. Emarsys
+// does not report it, so it is UpsertOutcomeUnknown unless WithUpsertLookup is
+// used.
+type UpsertOutcome int
+
+const (
+	UpsertOutcomeUnknown UpsertOutcome = iota
+	UpsertOutcomeCreated
+	UpsertOutcomeUpdated
+)
+
+type UpsertResult struct {
+	ContactResult
+	Outcome UpsertOutcome
+	// OutcomeErr is the error of the lookup for the contact, if WithUpsertLookup
+	// is used and the lookup failed. Outcome is UpsertOutcomeUnknown then.
+	OutcomeErr error
+}
+
+type UpsertOptions func(o *upsertOptions)
+
+type upsertOptions struct {
+	lookup       bool
+	batchOptions []BatchOptions
+}
+
+// WithUpsertLookup makes UpsertMany look up the key values before the update
+// to guess which contacts get created. The guess is best-effort: a contact
+// created or deleted by another writer between the two requests is reported
+// wrongly, and if the lookup fails the outcomes are left unknown.
+func WithUpsertLookup() UpsertOptions {
+	return func(o *upsertOptions) {
+		o.lookup = true
+	}
+}
+
+// WithUpsertBatch configures how UpsertMany splits the contacts into requests,
+// for the lookup as well as for the update.
+func WithUpsertBatch(batchOptions ...BatchOptions) UpsertOptions {
+	return func(o *upsertOptions) {
+		o.batchOptions = append(o.batchOptions, batchOptions...)
+	}
+}
+
+func (u *Users) Upsert(user User, keyID int, opts ...UpsertOptions) (*UpsertResult, error) {
+	return u.UpsertContext(context.Background(), user, keyID, opts...)
+}
+
+// UpsertContext updates the contact identified by keyID, creating it if it
+// does not exist yet. The per-contact error, if any, is returned as error.
+func (u *Users) UpsertContext(ctx context.Context, user User, keyID int, opts ...UpsertOptions) (*UpsertResult, error) {
+	results, err := u.UpsertManyContext(ctx, []User{user}, keyID, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if results[0].Err != nil {
+		return nil, results[0].Err
+	}
+
+	return &results[0], nil
+}
+
+func (u *Users) UpsertMany(users []User, keyID int, opts ...UpsertOptions) ([]UpsertResult, error) {
+	return u.UpsertManyContext(context.Background(), users, keyID, opts...)
+}
+
+// UpsertManyContext updates contacts using create_if_not_exists, so missing
+// contacts are created by the same request.
+func (u *Users) UpsertManyContext(ctx context.Context, users []User, keyID int, opts ...UpsertOptions) ([]UpsertResult, error) {
+	o := &upsertOptions{}
+	for _, f := range opts {
+		f(o)
+	}
+
+	var (
+		outcomes      map[string]UpsertOutcome
+		outcomeErrors map[string]error
+		lookupErr     error
+	)
+
+	if o.lookup {
+		outcomes, outcomeErrors, lookupErr = u.lookupUpsertOutcomes(ctx, keyID, users, o.batchOptions)
+	}
+
+	query := url.Values{}
+	query.Set("create_if_not_exists", "1")
+
+	path := &url.URL{
+		Path:     "/v2/contact",
+		RawQuery: query.Encode(),
+	}
+
+	contactResults, err := u.sendContactsBatched(ctx, requestPut, path.String(), users, keyID, o.batchOptions)
+
+	results := make([]UpsertResult, len(contactResults))
+	for i, result := range contactResults {
+		results[i] = UpsertResult{ContactResult: result}
+
+		if result.Err != nil {
+			continue
+		}
+
+		if lookupErr != nil {
+			results[i].OutcomeErr = lookupErr
+		} else {
+			results[i].Outcome = outcomes[result.KeyValue]
+			results[i].OutcomeErr = outcomeErrors[result.KeyValue]
+		}
+	}
+
+	return results, err
+}
+
+// lookupUpsertOutcomes guesses the outcome for the key values of users from
+// whether a contact exists for them. Key values the lookup failed for are
+// returned with their error instead.
+func (u *Users) lookupUpsertOutcomes(ctx context.Context, keyID int, users []User, opts []BatchOptions) (map[string]UpsertOutcome, map[string]error, error) {
+	values := make([]string, len(users))
+	for i, user := range users {
+		values[i] = user.Data[keyID]
+	}
+
+	info, err := u.GetUsersInfoContext(ctx, strconv.Itoa(keyID), values, nil, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	outcomes := make(map[string]UpsertOutcome, len(values))
+	for keyValue := range info.Users {
+		outcomes[keyValue] = UpsertOutcomeUpdated
+	}
+	for _, keyValue := range info.NotFound {
+		outcomes[keyValue] = UpsertOutcomeCreated
+	}
+
+	return outcomes, info.Errors, nil
+}
@@ -0,0 +1,117 @@
+package gomarsys
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsers_UpsertMany(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Path, "/v2/contact?create_if_not_exists=1")
+		assert.Equal(t, req.Method, RequestMethod(requestPut))
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"ids":[101,102]}}`), nil)
+
+	user := NewUsers(client)
+	results, err := user.UpsertMany([]User{
+		{Data: map[int]string{EMail: "a@test.ru", FirstName: "A"}},
+		{Data: map[int]string{EMail: "b@test.ru", FirstName: "B"}},
+	}, EMail)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "101", results[0].ID)
+	assert.Equal(t, UpsertOutcomeUnknown, results[0].Outcome)
+	assert.Equal(t, "102", results[1].ID)
+	assert.Equal(t, UpsertOutcomeUnknown, results[1].Outcome)
+	client.(*ClientMock).AssertNumberOfCalls(t, "Send", 1)
+}
+
+func TestUsers_UpsertManyLookup(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/contact/getdata"
+	})).Run(func(args mock.Arguments) {
+		req := args.Get(0).(*Request)
+
+		assert.Equal(t, req.Method, RequestMethod(requestPost))
+		var v struct {
+			KeyValues []string `json:"keyValues"`
+		}
+		require.NoError(t, json.Unmarshal(req.Body, &v))
+		assert.Equal(t, []string{"a@test.ru", "b@test.ru"}, v.KeyValues)
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"errors":[{"key":"b@test.ru","errorCode":2008,"errorMsg":"No contact found with the external id: 3"}],"result":[{"3":"a@test.ru","id":"101"}]}}`), nil)
+
+	client.(*ClientMock).On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/contact?create_if_not_exists=1"
+	})).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"ids":[101,102]}}`), nil)
+
+	user := NewUsers(client)
+	results, err := user.UpsertMany([]User{
+		{Data: map[int]string{EMail: "a@test.ru", FirstName: "A"}},
+		{Data: map[int]string{EMail: "b@test.ru", FirstName: "B"}},
+	}, EMail, WithUpsertLookup())
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, UpsertOutcomeUpdated, results[0].Outcome)
+	assert.Equal(t, UpsertOutcomeCreated, results[1].Outcome)
+}
+
+func TestUsers_UpsertLookupFailure(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/contact/getdata"
+	})).Return([]byte(nil), errors.New("connection reset"))
+
+	client.(*ClientMock).On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/contact?create_if_not_exists=1"
+	})).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"ids":[101]}}`), nil)
+
+	user := NewUsers(client)
+	result, err := user.Upsert(User{Data: map[int]string{EMail: "a@test.ru"}}, EMail, WithUpsertLookup())
+	require.NoError(t, err)
+	assert.Equal(t, "101", result.ID)
+	assert.Equal(t, UpsertOutcomeUnknown, result.Outcome)
+
+	var userError *UserError
+	require.True(t, errors.As(result.OutcomeErr, &userError))
+	assert.EqualError(t, userError.Unwrap(), "connection reset")
+}
+
+func TestUsers_UpsertManyLookupKeyError(t *testing.T) {
+	client := NewClientMock()
+	client.(*ClientMock).On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/contact/getdata"
+	})).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"errors":[{"key":"b@test.ru","errorCode":2010,"errorMsg":"More than one contact found with the external id: 3"}],"result":[{"3":"a@test.ru","id":"101"}]}}`), nil)
+
+	client.(*ClientMock).On("Send", mock.MatchedBy(func(r *Request) bool {
+		return r.Path == "/v2/contact?create_if_not_exists=1"
+	})).Run(func(args mock.Arguments) {
+		var v struct {
+			Contacts []map[string]string `json:"contacts"`
+		}
+		require.NoError(t, json.Unmarshal(args.Get(0).(*Request).Body, &v))
+		assert.Len(t, v.Contacts, 1)
+	}).Return([]byte(`{"replyCode":0,"replyText":"OK","data":{"ids":[101]}}`), nil)
+
+	user := NewUsers(client)
+	results, err := user.UpsertMany([]User{
+		{Data: map[int]string{EMail: "a@test.ru"}},
+		{Data: map[int]string{EMail: "b@test.ru"}},
+	}, EMail, WithUpsertLookup(), WithUpsertBatch(WithBatchSize(1)))
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, UpsertOutcomeUpdated, results[0].Outcome)
+	assert.NoError(t, results[0].OutcomeErr)
+	assert.Equal(t, UpsertOutcomeUnknown, results[1].Outcome)
+	assert.True(t, errors.Is(results[1].OutcomeErr, ErrAmbiguousContact))
+}